@@ -0,0 +1,86 @@
+package idgen
+
+import (
+    "fmt"
+    "sync/atomic"
+)
+
+// ------------- 無鎖產生器 ------------- //
+
+// atomicSeqBits 為打包狀態中序列號佔用的位元數，其餘高位 48 bits 存放毫秒時間戳
+const atomicSeqBits = seqBits
+
+// AtomicGenerator 以單一 64-bit atomic 狀態 (timestamp<<16 | sequence) 搭配 CAS 產生 ID
+// 高並發下不需互斥鎖，呼叫端不會彼此序列化等待
+//
+// 與 Generator 的差異：
+//   - 時鐘回撥時不升級 epoch，而是沿用上次時間戳繼續遞增序列號 (邏輯時間不倒退)
+//   - 序列號用盡時不睡眠，直接借用下一毫秒；持續超過每毫秒 65536 個時，時間戳會暫時領先實際時間
+//   - 狀態中的時間戳為 48 bits，自起算點約可使用 8900 年
+//   - 不支援 StateStore、RollbackPolicy、SequencePolicy、Metrics、hooks、日誌等行為選項，指定時 NewAtomic 回傳錯誤
+type AtomicGenerator struct {
+    base        *Generator // 持有時鐘、NodeIDProvider 等資源，Release 時歸還
+    customEpoch int64
+    clock       Clock
+    epoch       uint16
+    regionID    uint16
+    nodeID      uint16
+
+    state atomic.Uint64 // lastMillis<<16 | sequence
+}
+
+// NewAtomic 以與 New 相同的選項建立 AtomicGenerator
+func NewAtomic(opts ...Option) (*AtomicGenerator, error) {
+    g, err := New(opts...)
+    if err != nil {
+        return nil, err
+    }
+    err = g.requireDefaults("AtomicGenerator")
+    if err == nil {
+        err = g.requireBasic("AtomicGenerator")
+    }
+    if err != nil {
+        g.Release()
+        return nil, err
+    }
+    return &AtomicGenerator{
+        base:        g,
+        customEpoch: g.customEpoch,
+        clock:       g.clock,
+        epoch:       g.epoch,
+        regionID:    uint16(g.regionID),
+        nodeID:      uint16(g.nodeID),
+    }, nil
+}
+
+// Release 歸還建立時由 NodeIDProvider 取得的資源並停止背景工作 (例如 WithCachedClock 的更新)
+func (a *AtomicGenerator) Release() {
+    a.base.Release()
+}
+
+// Next 產生下一個唯一且有序的 ID (lock‑free，thread‑safe)
+func (a *AtomicGenerator) Next() (ID, error) {
+    for {
+        old := a.state.Load()
+        last, seq := old>>atomicSeqBits, old&maxSequence
+        ms := a.clock.Now().UnixMilli() - a.customEpoch
+        if ms < 0 {
+            return ID{}, fmt.Errorf("%w: %d ms early", ErrClockBeforeEpoch, -ms)
+        }
+        now := uint64(ms)
+
+        var ts, next uint64
+        switch {
+        case now > last:
+            ts, next = now, 0
+        case seq < maxSequence:
+            ts, next = last, seq+1
+        default: // 序列號用盡：借用下一毫秒
+            ts, next = last+1, 0
+        }
+
+        if a.state.CompareAndSwap(old, ts<<atomicSeqBits|next) {
+            return makeID(a.epoch, ts, a.regionID, a.nodeID, uint16(next)), nil
+        }
+    }
+}
@@ -0,0 +1,101 @@
+package idgen_test
+
+import (
+    "context"
+    "path/filepath"
+    "testing"
+    "time"
+
+    "github.com/pascal910107/idgen"
+)
+
+func TestNewAtomicRejectsUnsupportedOptions(t *testing.T) {
+    state := filepath.Join(t.TempDir(), "state.json")
+    tests := []struct {
+        name string
+        opt  idgen.Option
+    }{
+        {"state store", idgen.WithStateStore(idgen.NewFileStateStore(state))},
+        {"rollback policy", idgen.WithRollbackPolicy(idgen.ReturnError())},
+        {"sequence policy", idgen.WithSequencePolicy(idgen.SequenceFail)},
+        {"metrics", idgen.WithMetrics(idgen.MetricsFuncs{})},
+        {"hooks", idgen.OnClockRollback(func(time.Duration) {})},
+        {"journal", idgen.WithJournal(idgen.NewJournalWriter(nil))},
+        {"layout", idgen.WithLayout(idgen.Layout{EpochBits: 16, TimestampBits: 64, RegionBits: 8, NodeBits: 24, SequenceBits: 16})},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if a, err := idgen.NewAtomic(tt.opt); err == nil {
+                a.Release()
+                t.Fatalf("NewAtomic accepted %s", tt.name)
+            }
+        })
+    }
+}
+
+func TestAtomicReleaseStopsBackgroundWork(t *testing.T) {
+    released := false
+    a, err := idgen.NewAtomic(idgen.WithNodeIDProvider(t.Context(), idgen.NodeIDProviderFunc(
+        func(ctx context.Context) (uint16, uint16, func(), error) {
+            return 1, 2, func() { released = true }, nil
+        })))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, err := a.Next(); err != nil {
+        t.Fatal(err)
+    }
+    if released {
+        t.Fatal("node id released before Release")
+    }
+    a.Release()
+    if !released {
+        t.Fatal("Release did not return the node id lease")
+    }
+}
+
+func TestAtomicUniqueAndOrdered(t *testing.T) {
+    a, err := idgen.NewAtomic(idgen.WithRegionNode(1, 2))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer a.Release()
+
+    var prev idgen.ID
+    for i := range 200_000 {
+        id, err := a.Next()
+        if err != nil {
+            t.Fatal(err)
+        }
+        if id.Compare(prev) <= 0 {
+            t.Fatalf("id %d: %s not after %s", i, id, prev)
+        }
+        prev = id
+    }
+}
+
+func BenchmarkGeneratorParallel(b *testing.B) {
+    g, err := idgen.New()
+    if err != nil {
+        b.Fatal(err)
+    }
+    defer g.Release()
+    b.RunParallel(func(pb *testing.PB) {
+        for pb.Next() {
+            g.Next()
+        }
+    })
+}
+
+func BenchmarkAtomicParallel(b *testing.B) {
+    a, err := idgen.NewAtomic()
+    if err != nil {
+        b.Fatal(err)
+    }
+    defer a.Release()
+    b.RunParallel(func(pb *testing.PB) {
+        for pb.Next() {
+            a.Next()
+        }
+    })
+}
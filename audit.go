@@ -0,0 +1,123 @@
+package idgen
+
+import (
+    "encoding/json"
+    "io"
+    "sync"
+)
+
+// ------------- 發號稽核 ------------- //
+
+// AuditRecord 描述同一時間戳內連續發出的一段 ID：時間戳 Timestamp 的序列號 FirstSeq 至 LastSeq
+// 相較於 JournalWriter 逐一記錄 ID，每個時間戳通常只有一筆記錄，仍可驗證任一 ID 是否由此 Generator 發出
+// 序列號為欄位的原始值，啟用 WithEntropyBits 時範圍涵蓋所有可能的隨機低位
+type AuditRecord struct {
+    Epoch     uint16 `json:"epoch"`
+    Region    uint32 `json:"region"`
+    Node      uint32 `json:"node"`
+    Timestamp uint64 `json:"timestamp"`
+    FirstSeq  uint32 `json:"first_seq"`
+    LastSeq   uint32 `json:"last_seq"`
+}
+
+// Contains 回傳依 l 解碼的 id 是否落在此記錄的範圍內
+// 啟用 WithRandomSequenceStart 時範圍可能回繞，此時 FirstSeq 大於 LastSeq
+func (r AuditRecord) Contains(l Layout, id ID) bool {
+    f := l.Decode(id)
+    if f.Epoch != r.Epoch || f.Region != r.Region || f.Node != r.Node || f.TimestampMillis != r.Timestamp {
+        return false
+    }
+    if r.FirstSeq <= r.LastSeq {
+        return f.Sequence >= r.FirstSeq && f.Sequence <= r.LastSeq
+    }
+    return f.Sequence >= r.FirstSeq || f.Sequence <= r.LastSeq
+}
+
+// AuditSink 接收 AuditRecord，於持有 Generator 內部鎖時同步呼叫，應盡快返回
+// 寫入失敗不會影響 ID 的產生，實作需自行處理或保存錯誤
+type AuditSink interface {
+    Audit(AuditRecord)
+}
+
+// AuditSinkFunc 讓一般函式實作 AuditSink
+type AuditSinkFunc func(AuditRecord)
+
+// Audit 實作 AuditSink
+func (f AuditSinkFunc) Audit(r AuditRecord) { f(r) }
+
+// WithAudit 將發出的 ID 依時間戳彙整為 AuditRecord 交給 sink
+// 一段記錄在下一個時間戳開始 (或序列號不連續) 時才送出，最後一段於 Close 時送出
+func WithAudit(sink AuditSink) Option {
+    return func(g *Generator) error {
+        g.auditSink = sink
+        return nil
+    }
+}
+
+// audit 將剛以 g.sequence 與時間戳 ts 發出的 ID 併入目前的記錄，呼叫端須持有 g.mu
+func (g *Generator) audit(ts uint64) {
+    if g.auditSink == nil {
+        return
+    }
+    g.auditRange(ts, g.sequence, g.sequence)
+}
+
+// auditRange 將時間戳 ts 上計數器 first 至 last 的一段併入目前的記錄，無法接續時先送出目前的記錄
+// 記錄中的時間戳與序列號為實際寫入 ID 的值 (已套用時間戳模糊化、隨機起點與隨機低位)
+func (g *Generator) auditRange(ts uint64, first, last uint32) {
+    e, mask := g.entropyBits, g.layout.MaxSequence()>>g.entropyBits
+    cur := &g.auditCur
+    embedded := g.privacy.embed(ts)
+    if g.auditOpen && cur.Epoch == g.epoch && cur.Timestamp == embedded && cur.Region == g.regionID &&
+        cur.Node == g.nodeID && first == g.auditNext {
+        cur.LastSeq = g.seqStart.apply(ts, last, mask)<<e | uint32(mask64(e))
+        g.auditNext = last + 1
+        return
+    }
+    g.flushAudit()
+    *cur = AuditRecord{
+        Epoch:     g.epoch,
+        Region:    g.regionID,
+        Node:      g.nodeID,
+        Timestamp: embedded,
+        FirstSeq:  g.seqStart.apply(ts, first, mask) << e,
+        LastSeq:   g.seqStart.apply(ts, last, mask)<<e | uint32(mask64(e)),
+    }
+    g.auditOpen, g.auditNext = true, last+1
+}
+
+// flushAudit 送出尚未送出的記錄，呼叫端須持有 g.mu
+func (g *Generator) flushAudit() {
+    if g.auditOpen {
+        g.auditOpen = false
+        g.auditSink.Audit(g.auditCur)
+    }
+}
+
+// JSONAuditSink 將 AuditRecord 以每行一個 JSON 物件寫入 io.Writer，並發安全
+type JSONAuditSink struct {
+    mu  sync.Mutex
+    enc *json.Encoder
+    err error
+}
+
+// NewJSONAuditSink 建立寫入 w 的 JSONAuditSink
+func NewJSONAuditSink(w io.Writer) *JSONAuditSink {
+    return &JSONAuditSink{enc: json.NewEncoder(w)}
+}
+
+// Audit 實作 AuditSink，發生錯誤後不再寫入
+func (s *JSONAuditSink) Audit(r AuditRecord) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    if s.err == nil {
+        s.err = s.enc.Encode(r)
+    }
+}
+
+// Err 回傳第一次寫入失敗的錯誤
+func (s *JSONAuditSink) Err() error {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.err
+}
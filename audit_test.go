@@ -0,0 +1,110 @@
+package idgen_test
+
+import (
+    "fmt"
+    "testing"
+    "time"
+
+    "github.com/pascal910107/idgen"
+    "github.com/pascal910107/idgen/testclock"
+)
+
+// checkAudit 確認每個 ID 恰好落在一筆記錄內，且同一時間戳的記錄首尾相接
+func checkAudit(t *testing.T, l idgen.Layout, records []idgen.AuditRecord, ids []idgen.ID) {
+    t.Helper()
+    for _, id := range ids {
+        n := 0
+        for _, r := range records {
+            if r.Contains(l, id) {
+                n++
+            }
+        }
+        if n != 1 {
+            t.Fatalf("id %s in %d audit records, want 1: %+v", id, n, records)
+        }
+    }
+    for i := 1; i < len(records); i++ {
+        prev, r := records[i-1], records[i]
+        if r.Timestamp == prev.Timestamp && r.FirstSeq != prev.LastSeq+1 {
+            t.Fatalf("records %d and %d not contiguous: %+v, %+v", i-1, i, prev, r)
+        }
+        if r.Timestamp < prev.Timestamp {
+            t.Fatalf("record %d goes back in time: %+v after %+v", i, r, prev)
+        }
+    }
+}
+
+func TestAuditRangesContiguous(t *testing.T) {
+    tests := []struct {
+        name string
+        opts []idgen.Option
+    }{
+        {"default", nil},
+        {"entropy bits", []idgen.Option{idgen.WithEntropyBits(4)}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var records []idgen.AuditRecord
+            sink := idgen.AuditSinkFunc(func(r idgen.AuditRecord) { records = append(records, r) })
+            g, clock, err := testclock.NewGenerator(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+                append(tt.opts, idgen.WithRegionNode(1, 2), idgen.WithAudit(sink))...)
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            var ids []idgen.ID
+            for tick := range 5 {
+                batch, err := g.NextN(10 + tick)
+                if err != nil {
+                    t.Fatal(err)
+                }
+                ids = append(ids, batch...)
+                clock.Advance(time.Millisecond)
+            }
+            if err := g.Close(); err != nil {
+                t.Fatal(err)
+            }
+            if len(records) != 5 {
+                t.Fatalf("got %d audit records, want one per timestamp: %+v", len(records), records)
+            }
+            checkAudit(t, g.Layout(), records, ids)
+        })
+    }
+}
+
+func TestAuditReserveBlock(t *testing.T) {
+    // 12 個隨機位元讓每個時間單位只有 16 個位置；先發出 15 個時區塊的第一個位置恰為該時間單位的最後一個
+    for _, before := range []int{1, 15} {
+        t.Run(fmt.Sprint(before), func(t *testing.T) {
+            var records []idgen.AuditRecord
+            sink := idgen.AuditSinkFunc(func(r idgen.AuditRecord) { records = append(records, r) })
+            g, _, err := testclock.NewGenerator(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+                idgen.WithRegionNode(1, 2), idgen.WithEntropyBits(12), idgen.WithAudit(sink))
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            ids, err := g.NextN(before)
+            if err != nil {
+                t.Fatal(err)
+            }
+            b, err := g.ReserveBlock(40)
+            if err != nil {
+                t.Fatal(err)
+            }
+            for i := range b.Len() {
+                id, _ := b.At(i)
+                ids = append(ids, id)
+            }
+            last, err := g.Next()
+            if err != nil {
+                t.Fatal(err)
+            }
+            ids = append(ids, last)
+            if err := g.Close(); err != nil {
+                t.Fatal(err)
+            }
+            checkAudit(t, g.Layout(), records, ids)
+        })
+    }
+}
@@ -0,0 +1,24 @@
+package idgen_test
+
+import (
+    "context"
+    "path/filepath"
+    "testing"
+
+    "github.com/pascal910107/idgen"
+)
+
+func TestNewBackfillReleasesOnRejectedOption(t *testing.T) {
+    released := false
+    provider := idgen.NodeIDProviderFunc(func(ctx context.Context) (uint16, uint16, func(), error) {
+        return 1, 2, func() { released = true }, nil
+    })
+    store := idgen.NewFileStateStore(filepath.Join(t.TempDir(), "state.json"))
+    if b, err := idgen.NewBackfill(idgen.WithNodeIDProvider(t.Context(), provider), idgen.WithStateStore(store)); err == nil {
+        b.Close()
+        t.Fatal("NewBackfill accepted a state store")
+    }
+    if !released {
+        t.Fatal("NewBackfill did not return the node id lease")
+    }
+}
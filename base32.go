@@ -0,0 +1,144 @@
+package idgen
+
+import (
+    "encoding/binary"
+    "fmt"
+    "math"
+    "strings"
+)
+
+// ------------- Crockford Base32 ------------- //
+
+// crockfordAlphabet 依 ASCII 遞增排列，編碼後字串比較與 bytes 比較一致
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// crockfordDecode 將字元映射回 5-bit 值 (不分大小寫，I/L→1、O→0)，0xFF 表示非法字元
+var crockfordDecode = func() [256]byte {
+    var t [256]byte
+    for i := range t {
+        t[i] = 0xFF
+    }
+    for i := 0; i < len(crockfordAlphabet); i++ {
+        c := crockfordAlphabet[i]
+        t[c] = byte(i)
+        if c >= 'A' && c <= 'Z' {
+            t[c+'a'-'A'] = byte(i)
+        }
+    }
+    t['I'], t['i'] = 1, 1
+    t['L'], t['l'] = 1, 1
+    t['O'], t['o'] = 0, 0
+    return t
+}()
+
+// Base32Crockford 回傳 26 字元的 Crockford Base32 表示
+// 128 bits 前補 2 個 0 bit 後每 5 bits 一字元，字典序與 ID 大小順序相同
+func (id ID) Base32Crockford() string {
+    return string(id.AppendBase32Crockford(make([]byte, 0, 26)))
+}
+
+// AppendBase32Crockford 將 Crockford Base32 字串附加到 dst 後回傳，dst 容量足夠時不配置記憶體
+func (id ID) AppendBase32Crockford(dst []byte) []byte {
+    hi := binary.BigEndian.Uint64(id[0:8])
+    lo := binary.BigEndian.Uint64(id[8:16])
+
+    var buf [26]byte
+    for i := 25; i >= 0; i-- {
+        buf[i] = crockfordAlphabet[lo&0x1F]
+        lo = lo>>5 | hi<<59
+        hi >>= 5
+    }
+    return append(dst, buf[:]...)
+}
+
+// ParseBase32Crockford 解析 26 字元 Crockford Base32 字串，一律視為 ID 本身的編碼
+// Parse 會將看似 ULID 的字串轉換為 ID；確定來源為 Base32Crockford 時可改用此函式
+func ParseBase32Crockford(s string) (ID, error) {
+    var id ID
+    if len(s) != 26 {
+        return id, fmt.Errorf("%w: base32 length %d", ErrInvalidLength, len(s))
+    }
+
+    var hi, lo uint64
+    for i := 0; i < len(s); i++ {
+        v := crockfordDecode[s[i]]
+        if v == 0xFF {
+            return id, fmt.Errorf("%w: base32 character %q", ErrInvalidEncoding, s[i])
+        }
+        if i == 0 && v > 7 { // 首字元只承載 3 bits，超過即溢位
+            return id, fmt.Errorf("%w: base32 value overflows 128 bits", ErrInvalidEncoding)
+        }
+        hi = hi<<5 | lo>>59
+        lo = lo<<5 | uint64(v)
+    }
+    binary.BigEndian.PutUint64(id[0:8], hi)
+    binary.BigEndian.PutUint64(id[8:16], lo)
+    return id, nil
+}
+
+// ------------- 檢查碼 ------------- //
+
+// crockfordCheckAlphabet 為 Crockford 規範的 mod 37 檢查碼字元集 (前 32 個與編碼字元集相同)
+const crockfordCheckAlphabet = crockfordAlphabet + "*~$=U"
+
+// Base32Check 回傳 Crockford Base32 加上一個 mod 37 檢查碼字元的 27 字元字串，供電話報讀或自發票抄寫等人工輸入場合
+// 檢查碼可偵測任一字元打錯與相鄰字元對調；字典序仍與 ID 順序相同
+func (id ID) Base32Check() string {
+    return string(id.AppendBase32Check(make([]byte, 0, 27)))
+}
+
+// AppendBase32Check 將 Base32Check 字串附加到 dst 後回傳，dst 容量足夠時不配置記憶體
+func (id ID) AppendBase32Check(dst []byte) []byte {
+    dst = id.AppendBase32Crockford(dst)
+    return append(dst, crockfordCheckAlphabet[id.mod37()])
+}
+
+// ParseBase32Check 解析 Base32Check 字串並驗證檢查碼，檢查碼不符時回傳 ErrChecksumMismatch
+// 不分大小寫，並忽略人工輸入時常見的連字號與空白，例如 "000000000d4r7-dtpg0000000-jcw"
+func ParseBase32Check(s string) (ID, error) {
+    var buf [27]byte
+    n := 0
+    for i := 0; i < len(s); i++ {
+        if c := s[i]; c != '-' && c != ' ' {
+            if n == len(buf) {
+                return ID{}, fmt.Errorf("%w: base32 with checksum longer than 27 characters", ErrInvalidLength)
+            }
+            buf[n] = c
+            n++
+        }
+    }
+    if n != len(buf) {
+        return ID{}, fmt.Errorf("%w: base32 with checksum length %d", ErrInvalidLength, n)
+    }
+
+    id, err := ParseBase32Crockford(string(buf[:26]))
+    if err != nil {
+        return ID{}, err
+    }
+    if v, want := checkValue(buf[26]), id.mod37(); v != want {
+        return ID{}, fmt.Errorf("%w: got %q, want %q", ErrChecksumMismatch, buf[26], crockfordCheckAlphabet[want])
+    }
+    return id, nil
+}
+
+// checkValue 回傳檢查碼字元的值 (不分大小寫，I/L/O 依編碼規則視為 1/0)，非法字元回傳 37
+func checkValue(c byte) uint64 {
+    if v := crockfordDecode[c]; v != 0xFF {
+        return uint64(v)
+    }
+    if c == 'u' {
+        c = 'U'
+    }
+    if i := strings.IndexByte(crockfordCheckAlphabet[32:], c); i >= 0 {
+        return uint64(32 + i)
+    }
+    return 37
+}
+
+// mod37 回傳 128 位元值除以 37 的餘數
+func (id ID) mod37() uint64 {
+    const pow64 = (math.MaxUint64%37 + 1) % 37 // 2^64 mod 37
+    hi := binary.BigEndian.Uint64(id[0:8])
+    lo := binary.BigEndian.Uint64(id[8:16])
+    return (hi%37*pow64 + lo%37) % 37
+}
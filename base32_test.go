@@ -0,0 +1,65 @@
+package idgen_test
+
+import (
+    "errors"
+    "strings"
+    "testing"
+
+    "github.com/pascal910107/idgen"
+)
+
+func TestParseGroupedBase32Check(t *testing.T) {
+    id := idgen.MustParse("00000000000d25e6baa0000100020000")
+    check := id.Base32Check()
+
+    group := func(sep string, size int) string {
+        var parts []string
+        for s := check; len(s) > 0; {
+            n := min(size, len(s))
+            parts = append(parts, s[:n])
+            s = s[n:]
+        }
+        return strings.Join(parts, sep)
+    }
+    tests := []struct {
+        name string
+        in   string
+    }{
+        {"plain", check},
+        {"lower case", strings.ToLower(check)},
+        {"groups of 5 (32 chars)", group("-", 5)},
+        {"groups of 3 with spaces", group(" ", 3)},
+        {"groups of 4", group("-", 4)},
+        {"36 chars like a UUID", group("-", 3) + "-"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := idgen.Parse(tt.in)
+            if err != nil {
+                t.Fatalf("Parse(%q): %v", tt.in, err)
+            }
+            if got != id {
+                t.Fatalf("Parse(%q) = %s, want %s", tt.in, got, id)
+            }
+        })
+    }
+
+    if n := len(group("-", 5)); n != 32 {
+        t.Fatalf("grouped length %d, test no longer covers the hex-length case", n)
+    }
+    bad := []byte(group("-", 5))
+    bad[0] = '1'
+    if _, err := idgen.Parse(string(bad)); !errors.Is(err, idgen.ErrChecksumMismatch) {
+        t.Fatalf("corrupted grouped input: err = %v, want ErrChecksumMismatch", err)
+    }
+}
+
+func TestParseUnchangedFormats(t *testing.T) {
+    id := idgen.MustParse("00000000000d25e6baa0000100020000")
+    for _, s := range []string{id.Hex(), id.UUIDString(), id.Base64URL(), id.Base32Crockford()} {
+        got, err := idgen.Parse(s)
+        if err != nil || got != id {
+            t.Fatalf("Parse(%q) = %s, %v", s, got, err)
+        }
+    }
+}
@@ -0,0 +1,114 @@
+package idgen
+
+import (
+    "bufio"
+    "fmt"
+    "io"
+)
+
+// ------------- 批次編碼 ------------- //
+
+// formatWidths 為各字串格式的固定長度
+var formatWidths = map[Format]int{
+    FormatHex:             32,
+    FormatUUID:            36,
+    FormatBase64URL:       22,
+    FormatBase32Crockford: 26,
+    FormatBase64Sortable:  22,
+    FormatBase32Check:     27,
+}
+
+// AppendEncode 將 id 依 f 編碼後附加到 dst，為 Encode 的不配置記憶體版本；FormatBinary 回傳錯誤
+func (id ID) AppendEncode(dst []byte, f Format) ([]byte, error) {
+    switch f {
+    case FormatHex:
+        return id.AppendHex(dst), nil
+    case FormatUUID:
+        return id.AppendUUID(dst), nil
+    case FormatBase64URL:
+        return id.AppendBase64URL(dst), nil
+    case FormatBase32Crockford:
+        return id.AppendBase32Crockford(dst), nil
+    case FormatBase64Sortable:
+        return id.AppendBase64Sortable(dst), nil
+    case FormatBase32Check:
+        return id.AppendBase32Check(dst), nil
+    default:
+        return dst, fmt.Errorf("idgen: unsupported string format %v", f)
+    }
+}
+
+// EncodeAll 將 ids 依 f 逐一編碼，結果與對每個 ID 呼叫 Encode 相同
+// 所有字串共用同一塊記憶體，配置次數與 ID 數量無關，適合匯出大量 ID；FormatBinary 回傳錯誤
+func EncodeAll(ids []ID, f Format) ([]string, error) {
+    width, ok := formatWidths[f]
+    if !ok {
+        return nil, fmt.Errorf("idgen: unsupported string format %v", f)
+    }
+    buf := make([]byte, 0, width*len(ids))
+    for _, id := range ids {
+        buf, _ = id.AppendEncode(buf, f)
+    }
+    all := string(buf)
+    out := make([]string, len(ids))
+    for i := range out {
+        out[i] = all[i*width : (i+1)*width]
+    }
+    return out, nil
+}
+
+// Encoder 將 ID 依指定格式逐行寫入 io.Writer，內部緩衝並重用編碼空間，適合串流匯出 CSV 或 ndjson
+// 寫入完畢後須呼叫 Flush；不可並發使用
+//
+//    enc := idgen.NewEncoder(w, idgen.FormatUUID)
+//    enc.Quote = true // ndjson：每行為一個 JSON 字串
+//    for _, id := range ids {
+//        if err := enc.Encode(id); err != nil { ... }
+//    }
+//    err := enc.Flush()
+type Encoder struct {
+    Quote bool // 以雙引號包住每個 ID (各格式皆不含需跳脫的字元，結果即為合法的 JSON 字串)
+
+    w   *bufio.Writer
+    f   Format
+    buf []byte
+}
+
+// NewEncoder 建立寫入 w 的 Encoder，f 不支援時於第一次 Encode 回傳錯誤
+func NewEncoder(w io.Writer, f Format) *Encoder {
+    return &Encoder{w: bufio.NewWriter(w), f: f, buf: make([]byte, 0, 40)}
+}
+
+// Encode 寫入一個 ID 與換行
+func (e *Encoder) Encode(id ID) error {
+    b := e.buf[:0]
+    if e.Quote {
+        b = append(b, '"')
+    }
+    b, err := id.AppendEncode(b, e.f)
+    if err != nil {
+        return err
+    }
+    if e.Quote {
+        b = append(b, '"')
+    }
+    b = append(b, '\n')
+    e.buf = b
+    _, err = e.w.Write(b)
+    return err
+}
+
+// EncodeAll 依序寫入 ids，遇到錯誤即停止
+func (e *Encoder) EncodeAll(ids []ID) error {
+    for _, id := range ids {
+        if err := e.Encode(id); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// Flush 將緩衝中的資料寫入底層 io.Writer
+func (e *Encoder) Flush() error {
+    return e.w.Flush()
+}
@@ -0,0 +1,228 @@
+// Package bench 量測目前硬體上 Generator 的產生速率、序列號用盡頻率與延遲分佈，供容量規劃使用
+//
+//    r, err := bench.Run(ctx, bench.Config{Duration: 2 * time.Second})
+//    fmt.Print(r)
+//
+// 也可以透過命令列執行：idgen bench -d 2s -p 8
+//
+// 每次呼叫 Next 皆以 time.Now 計時，量得的速率會略低於 go test -bench 的結果，
+// 但延遲包含序列號用盡時等待下一毫秒的時間，較接近正式環境的尾端延遲
+package bench
+
+import (
+    "context"
+    "fmt"
+    "math/bits"
+    "runtime"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "time"
+
+    "github.com/pascal910107/idgen"
+)
+
+// Config 為量測參數，零值欄位使用預設值
+type Config struct {
+    Duration   time.Duration  // 每個階段的量測時間，預設 1s
+    Goroutines int            // 平行階段的 goroutine 數量，預設 GOMAXPROCS
+    Options    []idgen.Option // 建立 Generator 的選項，每個階段各自建立新的 Generator
+}
+
+// Result 為單一階段的量測結果
+type Result struct {
+    Goroutines int           `json:"goroutines"`
+    IDs        uint64        `json:"ids"`
+    Elapsed    time.Duration `json:"elapsed_ns"`
+    Exhausted  uint64        `json:"sequence_exhausted"` // 序列號用盡 (需等待下一毫秒) 的次數
+    Errors     uint64        `json:"errors"`             // Next 回傳錯誤的次數，例如 SequenceFail 時用盡
+    P50        time.Duration `json:"p50_ns"`
+    P99        time.Duration `json:"p99_ns"`
+    P999       time.Duration `json:"p999_ns"`
+    Max        time.Duration `json:"max_ns"`
+}
+
+// PerSecond 回傳每秒產生的 ID 數量
+func (r Result) PerSecond() float64 {
+    if r.Elapsed <= 0 {
+        return 0
+    }
+    return float64(r.IDs) / r.Elapsed.Seconds()
+}
+
+// ExhaustedPerSecond 回傳每秒序列號用盡的次數
+func (r Result) ExhaustedPerSecond() float64 {
+    if r.Elapsed <= 0 {
+        return 0
+    }
+    return float64(r.Exhausted) / r.Elapsed.Seconds()
+}
+
+// Report 為完整的量測結果
+type Report struct {
+    GOOS       string `json:"goos"`
+    GOARCH     string `json:"goarch"`
+    NumCPU     int    `json:"num_cpu"`
+    GOMAXPROCS int    `json:"gomaxprocs"`
+    Single     Result `json:"single"`
+    Parallel   Result `json:"parallel"`
+}
+
+// String 回傳易讀的表格
+func (r Report) String() string {
+    var b strings.Builder
+    fmt.Fprintf(&b, "%s/%s, %d CPUs, GOMAXPROCS=%d\n", r.GOOS, r.GOARCH, r.NumCPU, r.GOMAXPROCS)
+    fmt.Fprintf(&b, "%-10s %10s %14s %12s %10s %10s %10s %10s\n",
+        "phase", "goroutines", "ids/s", "exhausted/s", "p50", "p99", "p99.9", "max")
+    for _, row := range []struct {
+        name string
+        r    Result
+    }{{"single", r.Single}, {"parallel", r.Parallel}} {
+        fmt.Fprintf(&b, "%-10s %10d %14.0f %12.1f %10s %10s %10s %10s\n",
+            row.name, row.r.Goroutines, row.r.PerSecond(), row.r.ExhaustedPerSecond(),
+            row.r.P50, row.r.P99, row.r.P999, row.r.Max)
+        if row.r.Errors > 0 {
+            fmt.Fprintf(&b, "%-10s %d errors\n", "", row.r.Errors)
+        }
+    }
+    return b.String()
+}
+
+// Run 依序執行單執行緒與平行兩個階段，ctx 取消時提前結束並回傳 ctx 的錯誤
+func Run(ctx context.Context, c Config) (Report, error) {
+    if c.Duration <= 0 {
+        c.Duration = time.Second
+    }
+    if c.Goroutines <= 0 {
+        c.Goroutines = runtime.GOMAXPROCS(0)
+    }
+    r := Report{GOOS: runtime.GOOS, GOARCH: runtime.GOARCH, NumCPU: runtime.NumCPU(), GOMAXPROCS: runtime.GOMAXPROCS(0)}
+
+    var err error
+    if r.Single, err = Measure(ctx, c.Duration, 1, c.Options...); err != nil {
+        return r, err
+    }
+    if r.Parallel, err = Measure(ctx, c.Duration, c.Goroutines, c.Options...); err != nil {
+        return r, err
+    }
+    return r, nil
+}
+
+// Measure 以 opts 建立新的 Generator，由 goroutines 個 goroutine 同時呼叫 Next 持續 d
+func Measure(ctx context.Context, d time.Duration, goroutines int, opts ...idgen.Option) (Result, error) {
+    g, err := idgen.New(opts...)
+    if err != nil {
+        return Result{}, err
+    }
+    defer g.Release()
+
+    ctx, cancel := context.WithTimeout(ctx, d)
+    defer cancel()
+    var stop atomic.Bool
+    go func() {
+        <-ctx.Done()
+        stop.Store(true)
+    }()
+
+    hists := make([]histogram, goroutines)
+    var wg sync.WaitGroup
+    before := g.Stats()
+    start := time.Now()
+    for i := range goroutines {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            h := &hists[i]
+            for !stop.Load() {
+                t := time.Now()
+                _, err := g.Next()
+                h.add(time.Since(t))
+                if err != nil {
+                    h.errors++
+                }
+            }
+        }()
+    }
+    wg.Wait()
+    elapsed := time.Since(start)
+    after := g.Stats()
+
+    if err := ctx.Err(); err != nil && err != context.DeadlineExceeded {
+        return Result{}, err
+    }
+
+    var h histogram
+    r := Result{Goroutines: goroutines, Elapsed: elapsed, Exhausted: after.SequenceExhausted - before.SequenceExhausted}
+    for i := range hists {
+        h.merge(&hists[i])
+    }
+    r.Errors = h.errors
+    r.IDs = h.count - r.Errors
+    r.P50, r.P99, r.P999, r.Max = h.quantile(0.5), h.quantile(0.99), h.quantile(0.999), h.max
+    return r, nil
+}
+
+// ------------- 延遲分佈 ------------- //
+
+// subBuckets 為每個 2 的冪次區間再細分的桶數，分位數的相對誤差約為 1/subBuckets
+const subBuckets = 8
+
+// histogram 為對數線性的延遲分佈，固定大小不需配置記憶體
+type histogram struct {
+    counts [64 * subBuckets]uint64
+    count  uint64
+    errors uint64
+    max    time.Duration
+}
+
+// bucket 回傳 ns 所在的桶；[2^k, 2^(k+1)) 依最高位元之後的 3 個位元再分為 subBuckets 個桶
+func bucket(ns uint64) int {
+    if ns < subBuckets {
+        return int(ns)
+    }
+    k := bits.Len64(ns) - 1 // k >= 3
+    return (k-2)*subBuckets + int(ns>>(k-3)&(subBuckets-1))
+}
+
+// lowerBound 回傳桶 i 的下界 (ns)
+func lowerBound(i int) uint64 {
+    if i < subBuckets {
+        return uint64(i)
+    }
+    k := i/subBuckets + 2
+    return (subBuckets + uint64(i%subBuckets)) << (k - 3)
+}
+
+func (h *histogram) add(d time.Duration) {
+    if d < 0 {
+        d = 0
+    }
+    h.counts[bucket(uint64(d))]++
+    h.count++
+    h.max = max(h.max, d)
+}
+
+func (h *histogram) merge(o *histogram) {
+    for i, n := range o.counts {
+        h.counts[i] += n
+    }
+    h.count += o.count
+    h.errors += o.errors
+    h.max = max(h.max, o.max)
+}
+
+// quantile 回傳第 q 分位數所在桶的下界，不超過最大值
+func (h *histogram) quantile(q float64) time.Duration {
+    if h.count == 0 {
+        return 0
+    }
+    rank := uint64(q * float64(h.count))
+    var seen uint64
+    for i, n := range h.counts {
+        seen += n
+        if seen > rank {
+            return min(time.Duration(lowerBound(i)), h.max)
+        }
+    }
+    return h.max
+}
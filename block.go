@@ -0,0 +1,139 @@
+package idgen
+
+import (
+    "context"
+    "errors"
+    "fmt"
+)
+
+// ------------- 預留區塊 ------------- //
+
+// Block 為 ReserveBlock 預留的一段連續 ID 空間：時間戳 Start 的序列號 FirstSeq 起，到時間戳 End 的序列號 LastSeq 止
+// Block 只包含純資料，可序列化後交給離線或邊緣端，在不連線的情況下以 Next 逐一取出 ID；
+// Used 記錄已取出的數量，離線端保存 Block 時一併保存即可在重啟後接續
+type Block struct {
+    Layout      Layout `json:"layout"`
+    EntropyBits uint8  `json:"entropy_bits,omitempty"` // 序列號中保留給隨機值的低位數，區塊內一律為 0
+    Epoch       uint16 `json:"epoch"`
+    Region      uint32 `json:"region"`
+    Node        uint32 `json:"node"`
+    Start       uint64 `json:"start"`
+    End         uint64 `json:"end"`
+    FirstSeq    uint32 `json:"first_seq"` // 計數器值，不含 EntropyBits
+    LastSeq     uint32 `json:"last_seq"`
+    Used        uint64 `json:"used"`
+}
+
+// ReserveBlock 預留 n 個連續的 ID，之後此 Generator 產生的 ID 皆大於區塊內的 ID
+// 區塊以目前時間起算，同一時間單位的序列號用盡後延續到下一個時間單位；
+// 為避免後續 ID 的邏輯時間領先實際時間過多，區塊最多延伸到目前時間之後約一秒，n 超過時回傳錯誤
+//
+//    b, err := g.ReserveBlock(10000)
+//    // 將 b 以 JSON 傳給離線端
+//    for id, ok := b.Next(); ok; id, ok = b.Next() { ... }
+func (g *Generator) ReserveBlock(n int) (Block, error) {
+    if n <= 0 {
+        return Block{}, fmt.Errorf("idgen: invalid block size %d", n)
+    }
+    if g.privacy.ticks != 0 || g.seqStart.enabled {
+        return Block{}, errors.New("idgen: ReserveBlock does not support timestamp privacy or random sequence start")
+    }
+
+    g.mu.Lock()
+    defer g.mu.Unlock()
+
+    perTick := uint64(g.layout.MaxSequence()>>g.entropyBits) + 1
+    if limit := perTick * uint64(statePersistAhead/g.tick); uint64(n) > limit {
+        return Block{}, fmt.Errorf("idgen: block size %d exceeds %d", n, limit)
+    }
+
+    // 以 issueLocked 取得第一個位置，一併處理時鐘回撥、序列號用盡與關閉狀態；
+    // 其回傳的 ID 含隨機低位等，與區塊的 At(0) 不同，不寫入日誌
+    if _, err := g.issueLocked(context.Background(), g.currentMillis()); err != nil {
+        return Block{}, err
+    }
+    b := Block{
+        Layout:      g.layout,
+        EntropyBits: g.entropyBits,
+        Epoch:       g.epoch,
+        Region:      g.regionID,
+        Node:        g.nodeID,
+        Start:       g.lastMillis,
+        FirstSeq:    g.sequence,
+    }
+    last := uint64(b.FirstSeq) + uint64(n) - 1
+    b.End, b.LastSeq = b.Start+last/perTick, uint32(last%perTick)
+    if b.End > g.layout.MaxTimestamp() {
+        return Block{}, fmt.Errorf("idgen: timestamp %d overflows %d-bit layout", b.End, g.layout.TimestampBits)
+    }
+    if err := g.persist(b.End); err != nil {
+        return Block{}, err
+    }
+    // 與借用下一毫秒相同，邏輯時間可領先 lastClock，後續 ID 自 End 之後接續
+    g.lastMillis, g.sequence = b.End, b.LastSeq
+    if g.journal != nil {
+        ids := make([]ID, 0, min(n, journalMaxRecord))
+        for i := uint64(0); i < uint64(n); i++ {
+            id, _ := b.At(i)
+            if ids = append(ids, id); len(ids) == cap(ids) || i == uint64(n)-1 {
+                if err := g.logIssued(ids...); err != nil {
+                    return Block{}, err
+                }
+                ids = ids[:0]
+            }
+        }
+    }
+    if g.auditSink != nil && n > 1 { // 第一個位置已由 issueLocked 併入，其餘自第二個位置起依時間戳逐段接續
+        off := uint64(b.FirstSeq) + 1
+        for ts := b.Start + off/perTick; ts <= b.End; ts++ {
+            first, last := uint32(0), uint32(perTick-1)
+            if ts == b.Start {
+                first = uint32(off % perTick)
+            }
+            if ts == b.End {
+                last = b.LastSeq
+            }
+            g.auditRange(ts, first, last)
+        }
+    }
+    g.metrics.IncCounter(MetricIDsIssued, uint64(n))
+    return b, nil
+}
+
+// Len 回傳區塊內的 ID 總數
+func (b *Block) Len() uint64 {
+    return (b.End-b.Start)*b.perTick() + uint64(b.LastSeq) - uint64(b.FirstSeq) + 1
+}
+
+// Remaining 回傳尚未取出的 ID 數量
+func (b *Block) Remaining() uint64 {
+    return b.Len() - min(b.Used, b.Len())
+}
+
+// At 回傳區塊內第 i 個 ID (自 0 起)，i 超出範圍時 ok 為 false
+func (b *Block) At(i uint64) (id ID, ok bool) {
+    if i >= b.Len() {
+        return ID{}, false
+    }
+    off := uint64(b.FirstSeq) + i
+    return b.Layout.Encode(LayoutFields{
+        Epoch:           b.Epoch,
+        TimestampMillis: b.Start + off/b.perTick(),
+        Region:          b.Region,
+        Node:            b.Node,
+        Sequence:        uint32(off%b.perTick()) << b.EntropyBits,
+    }), true
+}
+
+// Next 取出下一個 ID 並遞增 Used，區塊用盡時 ok 為 false；不可並發呼叫
+func (b *Block) Next() (id ID, ok bool) {
+    id, ok = b.At(b.Used)
+    if ok {
+        b.Used++
+    }
+    return id, ok
+}
+
+func (b *Block) perTick() uint64 {
+    return uint64(b.Layout.MaxSequence()>>b.EntropyBits) + 1
+}
@@ -0,0 +1,149 @@
+package idgen_test
+
+import (
+    "bytes"
+    "encoding/json"
+    "errors"
+    "io"
+    "testing"
+    "time"
+
+    "github.com/pascal910107/idgen"
+)
+
+func TestReserveBlockJournal(t *testing.T) {
+    var buf bytes.Buffer
+    g, err := idgen.New(idgen.WithRegionNode(1, 2), idgen.WithEntropyBits(4), idgen.WithJournal(idgen.NewJournalWriter(&buf)))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer g.Release()
+
+    b, err := g.ReserveBlock(5000)
+    if err != nil {
+        t.Fatal(err)
+    }
+    r := idgen.NewJournalReader(&buf)
+    for i := range b.Len() {
+        want, _ := b.At(i)
+        got, err := r.Read()
+        if err != nil {
+            t.Fatalf("journal entry %d: %v", i, err)
+        }
+        if got != want {
+            t.Fatalf("journal entry %d = %s, want %s", i, got, want)
+        }
+    }
+    if id, err := r.Read(); !errors.Is(err, io.EOF) {
+        t.Fatalf("extra journal entry %s, %v", id, err)
+    }
+}
+
+func TestReserveBlockPositions(t *testing.T) {
+    // 12 個隨機位元讓每個時間單位只有 16 個位置，區塊跨越多個時間戳
+    g, err := idgen.New(idgen.WithRegionNode(1, 2), idgen.WithEntropyBits(12))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer g.Release()
+
+    b, err := g.ReserveBlock(50)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if b.Len() != 50 || b.Remaining() != 50 {
+        t.Fatalf("Len = %d, Remaining = %d, want 50", b.Len(), b.Remaining())
+    }
+    if b.End <= b.Start {
+        t.Fatalf("block [%d, %d] does not span several timestamps", b.Start, b.End)
+    }
+
+    var prev idgen.ID
+    for i := range b.Len() {
+        id, ok := b.At(i)
+        if !ok {
+            t.Fatalf("At(%d) out of range", i)
+        }
+        if id.Compare(prev) <= 0 {
+            t.Fatalf("At(%d) = %s not after %s", i, id, prev)
+        }
+        if f := g.Decode(id); f.Region != 1 || f.Node != 2 || f.Sequence&0xfff != 0 {
+            t.Fatalf("At(%d) fields = %+v", i, f)
+        }
+        prev = id
+    }
+    if _, ok := b.At(b.Len()); ok {
+        t.Fatal("At(Len) in range")
+    }
+
+    next, err := g.Next()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if next.Compare(prev) <= 0 {
+        t.Fatalf("Next = %s not after block end %s", next, prev)
+    }
+}
+
+func TestBlockNextResumes(t *testing.T) {
+    g, err := idgen.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer g.Release()
+    b, err := g.ReserveBlock(10)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    for range 4 {
+        b.Next()
+    }
+    data, err := json.Marshal(b)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var resumed idgen.Block
+    if err := json.Unmarshal(data, &resumed); err != nil {
+        t.Fatal(err)
+    }
+    if resumed.Remaining() != 6 {
+        t.Fatalf("Remaining after resume = %d, want 6", resumed.Remaining())
+    }
+    for i := uint64(4); i < 10; i++ {
+        got, ok := resumed.Next()
+        want, _ := b.At(i)
+        if !ok || got != want {
+            t.Fatalf("Next = %s, %v, want %s", got, ok, want)
+        }
+    }
+    if _, ok := resumed.Next(); ok {
+        t.Fatal("Next beyond block end")
+    }
+}
+
+func TestReserveBlockRejects(t *testing.T) {
+    tests := []struct {
+        name string
+        opts []idgen.Option
+        n    int
+    }{
+        {"zero", nil, 0},
+        {"negative", nil, -1},
+        {"too large", []idgen.Option{idgen.WithEntropyBits(15)}, 10_000},
+        {"random sequence start", []idgen.Option{idgen.WithRandomSequenceStart()}, 10},
+        {"timestamp privacy", []idgen.Option{idgen.WithTimestampPrivacy(time.Second)}, 10},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            g, err := idgen.New(tt.opts...)
+            if err != nil {
+                t.Fatal(err)
+            }
+            defer g.Release()
+            if _, err := g.ReserveBlock(tt.n); err == nil {
+                t.Fatalf("ReserveBlock(%d) succeeded", tt.n)
+            }
+        })
+    }
+}
@@ -0,0 +1,104 @@
+package idgen
+
+import (
+    "encoding/binary"
+    "fmt"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// ------------- MongoDB BSON 整合 ------------- //
+
+// bsonBinarySubtype 為 ID 寫入 BSON 時使用的 binary subtype (generic)
+const bsonBinarySubtype = 0x00
+
+// MarshalBSONValue 實作 bson.ValueMarshaler，以 16-byte BSON binary 儲存，可直接作為 _id
+// binary 依 bytes 比較，因此 MongoDB 索引中的排序與 ID 順序一致
+func (id ID) MarshalBSONValue() (bsontype.Type, []byte, error) {
+    data := make([]byte, 0, 4+1+len(id))
+    data = binary.LittleEndian.AppendUint32(data, uint32(len(id)))
+    data = append(data, bsonBinarySubtype)
+    data = append(data, id[:]...)
+    return bsontype.Binary, data, nil
+}
+
+// UnmarshalBSONValue 實作 bson.ValueUnmarshaler
+// 接受 16-byte binary (subtype 0x00 或 0x04 UUID)、Parse 可辨識的字串，以及 null
+func (id *ID) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+    switch t {
+    case bsontype.Null:
+        *id = ID{}
+        return nil
+    case bsontype.Binary:
+        if len(data) < 5 {
+            return fmt.Errorf("%w: truncated bson binary", ErrInvalidEncoding)
+        }
+        n, subtype := binary.LittleEndian.Uint32(data[0:4]), data[4]
+        if subtype != 0x00 && subtype != 0x04 {
+            return fmt.Errorf("%w: bson binary subtype 0x%02x", ErrInvalidEncoding, subtype)
+        }
+        if int(n) != len(id) || len(data) != 5+len(id) {
+            return fmt.Errorf("%w: bson binary length %d", ErrInvalidLength, n)
+        }
+        copy(id[:], data[5:])
+        return nil
+    case bsontype.String:
+        if len(data) < 5 || data[len(data)-1] != 0 {
+            return fmt.Errorf("%w: malformed bson string", ErrInvalidEncoding)
+        }
+        return id.UnmarshalText(data[4 : len(data)-1])
+    default:
+        return fmt.Errorf("%w: cannot decode bson %v into idgen.ID", ErrInvalidEncoding, t)
+    }
+}
+
+// ToObjectID 將 ID 轉為 12-byte MongoDB ObjectID (有損)，回傳值可直接指定給 primitive.ObjectID
+//
+// 位元配置：
+//
+//    Unix 秒 (32) │ region (16) │ node (16) │ epoch 低 8 bits │ 毫秒餘數 (10) │ sequence 低 14 bits
+//
+// ObjectID 排序仍以時間為主；epoch ≥ 256、sequence ≥ 16384 或時間超出 32-bit 秒數時
+// 無法無損表示，回傳 ErrInvalidEncoding 而不截斷
+func (id ID) ToObjectID() ([12]byte, error) {
+    var oid [12]byte
+    unixMs := CustomEpoch + int64(id.TimestampMillis())
+    switch {
+    case id.TimestampMillis() >= 1<<63 || unixMs < 0 || unixMs/1000 > 1<<32-1:
+        return oid, fmt.Errorf("%w: timestamp does not fit objectid", ErrInvalidEncoding)
+    case id.Epoch() > 0xFF:
+        return oid, fmt.Errorf("%w: epoch %d does not fit objectid", ErrInvalidEncoding, id.Epoch())
+    case id.Sequence() > 0x3FFF:
+        return oid, fmt.Errorf("%w: sequence %d does not fit objectid", ErrInvalidEncoding, id.Sequence())
+    }
+
+    binary.BigEndian.PutUint32(oid[0:4], uint32(unixMs/1000))
+    binary.BigEndian.PutUint16(oid[4:6], id.Region())
+    binary.BigEndian.PutUint16(oid[6:8], id.Node())
+    oid[8] = byte(id.Epoch())
+    tail := uint32(unixMs%1000)<<14 | uint32(id.Sequence())
+    oid[9], oid[10], oid[11] = byte(tail>>16), byte(tail>>8), byte(tail)
+    return oid, nil
+}
+
+// FromObjectID 為 ToObjectID 的反向轉換
+// 對驅動程式產生的一般 ObjectID，只有秒級時間具有意義，其餘欄位為其隨機值與計數器
+func FromObjectID(oid [12]byte) (ID, error) {
+    tail := uint32(oid[9])<<16 | uint32(oid[10])<<8 | uint32(oid[11])
+    ms := tail >> 14
+    if ms > 999 { // 一般 ObjectID 的計數器可能超出範圍，僅保留秒級時間
+        ms = 0
+    }
+    unixMs := int64(binary.BigEndian.Uint32(oid[0:4]))*int64(time.Second/time.Millisecond) + int64(ms)
+    if unixMs < CustomEpoch {
+        return ID{}, fmt.Errorf("%w: objectid timestamp before custom epoch", ErrInvalidEncoding)
+    }
+    return makeID(
+        uint16(oid[8]),
+        uint64(unixMs-CustomEpoch),
+        binary.BigEndian.Uint16(oid[4:6]),
+        binary.BigEndian.Uint16(oid[6:8]),
+        uint16(tail&0x3FFF),
+    ), nil
+}
@@ -0,0 +1,62 @@
+package idgen
+
+import "time"
+
+// ------------- 時間分桶 ------------- //
+
+// BucketSize 為時間分區的粒度
+type BucketSize uint8
+
+const (
+    // BucketHour 以小時分區，後綴格式 2006010215
+    BucketHour BucketSize = iota
+    // BucketDay 以日分區，後綴格式 20060102
+    BucketDay
+    // BucketMonth 以月分區，後綴格式 200601
+    BucketMonth
+)
+
+// suffixLayout 回傳分區後綴使用的時間格式
+func (b BucketSize) suffixLayout() string {
+    switch b {
+    case BucketHour:
+        return "2006010215"
+    case BucketDay:
+        return "20060102"
+    default:
+        return "200601"
+    }
+}
+
+// Truncate 將 t 轉為 UTC 後截斷至所在分桶的起點
+func (b BucketSize) Truncate(t time.Time) time.Time {
+    t = t.UTC()
+    switch b {
+    case BucketHour:
+        return t.Truncate(time.Hour)
+    case BucketDay:
+        return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+    default:
+        return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+    }
+}
+
+// Suffix 回傳 t 所在分桶的表名後綴，例如 2025010115、20250101、202501
+func (b BucketSize) Suffix(t time.Time) string {
+    return b.Truncate(t).Format(b.suffixLayout())
+}
+
+// HourBucket 回傳 id 時間戳所在小時的起點 (UTC)
+// 時間以 CustomEpoch 為起算點，自訂起算點的 ID 請以 BucketHour.Truncate(g.Time(id)) 計算
+func (id ID) HourBucket() time.Time { return BucketHour.Truncate(id.Time()) }
+
+// DayBucket 回傳 id 時間戳所在日的起點 (UTC)
+func (id ID) DayBucket() time.Time { return BucketDay.Truncate(id.Time()) }
+
+// MonthBucket 回傳 id 時間戳所在月的起點 (UTC)
+func (id ID) MonthBucket() time.Time { return BucketMonth.Truncate(id.Time()) }
+
+// BucketSuffix 回傳 id 所屬分區的表名後綴，可用於導向依時間分區的資料表，例如
+//
+//    table := "events_p" + id.BucketSuffix(idgen.BucketDay) // events_p20250101
+func (id ID) BucketSuffix(b BucketSize) string { return b.Suffix(id.Time()) }
@@ -0,0 +1,138 @@
+package idgen
+
+import (
+    "sync"
+    "sync/atomic"
+    "time"
+)
+
+// ------------- 時鐘抽象 ------------- //
+
+// Clock 提供 Generator 取得時間與等待的來源
+// 預設為系統時鐘；測試或特殊時間來源可透過 WithClock 替換
+type Clock interface {
+    Now() time.Time
+    Sleep(d time.Duration)
+}
+
+// systemClock 直接使用 time.Now / time.Sleep
+type systemClock struct{}
+
+func (systemClock) Now() time.Time        { return time.Now() }
+func (systemClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// SystemClock 回傳以系統時間為準的 Clock
+func SystemClock() Clock { return systemClock{} }
+
+// monotonicClock 以建立時的牆上時間為錨點，之後只依單調時鐘推進
+// 不受 NTP 調整或手動改時間影響，因此不會觸發 Generator 的回撥處理
+type monotonicClock struct {
+    reanchor time.Duration
+
+    mu   sync.Mutex
+    base time.Time // 錨點的 time.Now()，保留單調時鐘讀數
+    wall time.Time // 錨點對應的時間 (不含單調讀數)
+}
+
+// NewMonotonicClock 建立以單調時鐘推進的 Clock，錨定於建立當下的系統時間
+// reanchor > 0 時每隔 reanchor 重新對齊系統時間，但只會向前對齊：
+// 系統時間落後時維持目前時間，等系統時間追上後才跟進，因此回傳的時間永不倒退
+// reanchor 為 0 時永不重新對齊，長時間執行後可能與系統時間逐漸偏離
+func NewMonotonicClock(reanchor time.Duration) Clock {
+    now := time.Now()
+    return &monotonicClock{reanchor: reanchor, base: now, wall: now.Round(0)}
+}
+
+func (c *monotonicClock) Now() time.Time {
+    now := time.Now()
+
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    elapsed := now.Sub(c.base) // 兩者皆含單調讀數，相減只依單調時鐘
+    t := c.wall.Add(elapsed)
+    if c.reanchor > 0 && elapsed >= c.reanchor {
+        if wall := now.Round(0); wall.After(t) {
+            t = wall
+        }
+        c.base, c.wall = now, t
+    }
+    return t
+}
+
+func (c *monotonicClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// cachedClock 回傳背景定期更新的快取時間，省去熱路徑上每個 ID 一次的 time.Now
+type cachedClock struct {
+    src   Clock
+    mu    sync.Mutex // 序列化 refresh
+    now   atomic.Pointer[time.Time]
+    reads atomic.Uint32 // Now 的呼叫次數，每 cachedClockReads 次改為實際讀取
+}
+
+// cachedClockReads 為兩次實際讀取間最多回傳快取的次數
+// 背景更新在 CPU 忙碌時可能延遲數十毫秒才被排程，以呼叫次數限制快取落後的程度
+const cachedClockReads = 64
+
+// NewCachedClock 建立每隔 refresh 自 src 讀取一次時間的 Clock，Now 通常只讀取快取
+// Sleep 結束後會立即更新快取，因此序列號用盡而等待下一毫秒時不必等到下一次更新；
+// 背景更新未能及時執行時，每 64 次 Now 也會實際讀取一次
+// 快取落後 src 的程度只影響時間戳的精確度，不影響唯一性與遞增性；
+// refresh 大於 Generator 的時間單位時，同一時間戳涵蓋的實際時間變長，序列號也較容易用盡
+// src 為 nil 時使用 SystemClock，refresh 不大於 0 時使用 1ms；回傳的 stop 會停止背景更新並等待背景結束
+func NewCachedClock(src Clock, refresh time.Duration) (c Clock, stop func()) {
+    if src == nil {
+        src = SystemClock()
+    }
+    if refresh <= 0 {
+        refresh = time.Millisecond
+    }
+    cc := &cachedClock{src: src}
+    cc.refresh()
+
+    done := make(chan struct{})
+    exited := make(chan struct{})
+    go func() {
+        defer close(exited)
+
+        ticker := time.NewTicker(refresh)
+        defer ticker.Stop()
+        for {
+            select {
+            case <-done:
+                return
+            case <-ticker.C:
+                cc.refresh()
+            }
+        }
+    }()
+
+    var once sync.Once
+    return cc, func() {
+        once.Do(func() {
+            close(done)
+            <-exited
+        })
+    }
+}
+
+func (c *cachedClock) Now() time.Time {
+    if c.reads.Add(1)%cachedClockReads == 0 {
+        c.refresh()
+    }
+    return *c.now.Load()
+}
+
+func (c *cachedClock) Sleep(d time.Duration) {
+    c.src.Sleep(d)
+    c.refresh()
+}
+
+// refresh 以 src 的目前時間更新快取
+// 背景更新與 Sleep 可能同時進行，讀取與寫入皆持鎖，避免較早的讀數覆蓋較新的
+func (c *cachedClock) refresh() {
+    c.mu.Lock()
+    t := c.src.Now()
+    c.now.Store(&t)
+    c.mu.Unlock()
+}
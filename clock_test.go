@@ -0,0 +1,82 @@
+package idgen_test
+
+import (
+    "testing"
+    "time"
+
+    "github.com/pascal910107/idgen"
+    "github.com/pascal910107/idgen/testclock"
+)
+
+func TestCachedClockSleepRefreshes(t *testing.T) {
+    src := testclock.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+    c, stop := idgen.NewCachedClock(src, time.Hour)
+    defer stop()
+
+    before := c.Now()
+    c.Sleep(time.Millisecond)
+    if got := c.Now().Sub(before); got != time.Millisecond {
+        t.Fatalf("Now advanced %v after Sleep(1ms), want 1ms", got)
+    }
+}
+
+func TestCachedClockBoundsStaleness(t *testing.T) {
+    // 背景更新間隔遠大於測試時間，只能靠呼叫次數觸發實際讀取
+    src := testclock.New(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+    c, stop := idgen.NewCachedClock(src, time.Hour)
+    defer stop()
+
+    src.Advance(time.Second)
+    for range 64 {
+        c.Now()
+    }
+    if got := c.Now(); !got.Equal(src.Now()) {
+        t.Fatalf("cache still at %v after 64 reads, source at %v", got, src.Now())
+    }
+}
+
+func TestCachedClockGeneratorOrdered(t *testing.T) {
+    g, err := idgen.New(idgen.WithCachedClock(time.Millisecond))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer g.Release()
+
+    var prev idgen.ID
+    for i := range 200_000 {
+        id, err := g.Next()
+        if err != nil {
+            t.Fatal(err)
+        }
+        if id.Compare(prev) <= 0 {
+            t.Fatalf("id %d: %s not after %s", i, id, prev)
+        }
+        prev = id
+    }
+    if lag := time.Since(prev.Time()); lag > 50*time.Millisecond {
+        t.Fatalf("last id lags wall clock by %v", lag)
+    }
+}
+
+// BenchmarkNextSystemClock 與 BenchmarkNextCachedClock 比較每個 ID 呼叫 time.Now 與讀取快取的差異
+func BenchmarkNextSystemClock(b *testing.B) {
+    g, err := idgen.New()
+    if err != nil {
+        b.Fatal(err)
+    }
+    defer g.Release()
+    for b.Loop() {
+        g.Next()
+    }
+}
+
+func BenchmarkNextCachedClock(b *testing.B) {
+    g, err := idgen.New(idgen.WithCachedClock(time.Millisecond))
+    if err != nil {
+        b.Fatal(err)
+    }
+    defer g.Release()
+    for b.Loop() {
+        g.Next()
+    }
+}
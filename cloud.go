@@ -0,0 +1,176 @@
+package idgen
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "net/http"
+    "strings"
+    "time"
+)
+
+// ------------- 雲端中繼資料 ------------- //
+
+// 各雲端的中繼資料端點，僅能於對應雲端的執行個體內存取
+var (
+    ec2MetadataURL   = "http://169.254.169.254/latest"
+    gceMetadataURL   = "http://metadata.google.internal/computeMetadata/v1"
+    azureMetadataURL = "http://169.254.169.254/metadata"
+)
+
+// cloudMetadataTimeout 為查詢所有端點的總逾時；非雲端環境下端點不可達，避免啟動時久候
+const cloudMetadataTimeout = 2 * time.Second
+
+// CloudZone 為自中繼資料端點取得的部署位置
+type CloudZone struct {
+    Provider string // "aws"、"gcp" 或 "azure"
+    Zone     string // 可用區，例如 us-east-1a、us-central1-a、eastus-1
+    Region   string // 可用區所屬的區域，例如 us-east-1、us-central1、eastus
+}
+
+// DetectCloudZone 同時查詢 EC2 (IMDSv2)、GCE 與 Azure 的中繼資料端點，回傳第一個成功的結果
+// 皆失敗時 (例如不在雲端上執行) 回傳錯誤
+func DetectCloudZone(ctx context.Context) (CloudZone, error) {
+    ctx, cancel := context.WithTimeout(ctx, cloudMetadataTimeout)
+    defer cancel()
+
+    type result struct {
+        zone CloudZone
+        err  error
+    }
+    probes := []func(context.Context) (CloudZone, error){ec2Zone, gceZone, azureZone}
+    results := make(chan result, len(probes))
+    for _, probe := range probes {
+        go func() {
+            z, err := probe(ctx)
+            results <- result{z, err}
+        }()
+    }
+
+    var errs []error
+    for range probes {
+        r := <-results
+        if r.err == nil {
+            return r.zone, nil
+        }
+        errs = append(errs, r.err)
+    }
+    return CloudZone{}, fmt.Errorf("idgen: no cloud metadata endpoint available: %w", errors.Join(errs...))
+}
+
+// RegionFromCloudMetadata 以 DetectCloudZone 取得可用區，並依 zones 對應為區域 ID
+// zones 的鍵可為可用區 (us-east-1a) 或區域 (us-east-1)，可用區優先
+//
+//    region, err := idgen.RegionFromCloudMetadata(ctx, map[string]uint16{
+//        "us-east-1a": 1, "us-east-1b": 2, "europe-west1": 10,
+//    })
+func RegionFromCloudMetadata(ctx context.Context, zones map[string]uint16) (uint16, error) {
+    z, err := DetectCloudZone(ctx)
+    if err != nil {
+        return 0, err
+    }
+    return z.lookup(zones)
+}
+
+// WithCloudRegion 於 New 時以 RegionFromCloudMetadata 設定區域 ID
+func WithCloudRegion(ctx context.Context, zones map[string]uint16) Option {
+    return func(g *Generator) error {
+        region, err := RegionFromCloudMetadata(ctx, zones)
+        if err != nil {
+            return err
+        }
+        g.regionID = uint32(region)
+        return nil
+    }
+}
+
+func (z CloudZone) lookup(zones map[string]uint16) (uint16, error) {
+    if id, ok := zones[z.Zone]; ok {
+        return id, nil
+    }
+    if id, ok := zones[z.Region]; ok {
+        return id, nil
+    }
+    return 0, fmt.Errorf("%w: no region id configured for %s zone %s", ErrRegionOutOfRange, z.Provider, z.Zone)
+}
+
+// ec2Zone 以 IMDSv2 取得 session token 後讀取可用區
+func ec2Zone(ctx context.Context) (CloudZone, error) {
+    token, err := metadataGet(ctx, http.MethodPut, ec2MetadataURL+"/api/token",
+        map[string]string{"X-aws-ec2-metadata-token-ttl-seconds": "60"})
+    if err != nil {
+        return CloudZone{}, fmt.Errorf("aws: %w", err)
+    }
+    zone, err := metadataGet(ctx, http.MethodGet, ec2MetadataURL+"/meta-data/placement/availability-zone",
+        map[string]string{"X-aws-ec2-metadata-token": token})
+    if err != nil {
+        return CloudZone{}, fmt.Errorf("aws: %w", err)
+    }
+    // us-east-1a → us-east-1
+    return CloudZone{Provider: "aws", Zone: zone, Region: strings.TrimRight(zone, "abcdefghijklmnopqrstuvwxyz")}, nil
+}
+
+// gceZone 讀取 projects/<n>/zones/<zone> 形式的可用區
+func gceZone(ctx context.Context) (CloudZone, error) {
+    v, err := metadataGet(ctx, http.MethodGet, gceMetadataURL+"/instance/zone",
+        map[string]string{"Metadata-Flavor": "Google"})
+    if err != nil {
+        return CloudZone{}, fmt.Errorf("gcp: %w", err)
+    }
+    zone := v[strings.LastIndexByte(v, '/')+1:]
+    region := zone
+    if i := strings.LastIndexByte(zone, '-'); i > 0 {
+        region = zone[:i]
+    }
+    return CloudZone{Provider: "gcp", Zone: zone, Region: region}, nil
+}
+
+// azureZone 讀取 location 與 zone；未使用可用區的執行個體以 location 作為 Zone
+func azureZone(ctx context.Context) (CloudZone, error) {
+    v, err := metadataGet(ctx, http.MethodGet, azureMetadataURL+"/instance/compute?api-version=2021-02-01&format=json",
+        map[string]string{"Metadata": "true"})
+    if err != nil {
+        return CloudZone{}, fmt.Errorf("azure: %w", err)
+    }
+    var compute struct {
+        Location string `json:"location"`
+        Zone     string `json:"zone"`
+    }
+    if err := json.Unmarshal([]byte(v), &compute); err != nil {
+        return CloudZone{}, fmt.Errorf("azure: %w", err)
+    }
+    if compute.Location == "" {
+        return CloudZone{}, errors.New("azure: empty location")
+    }
+    z := CloudZone{Provider: "azure", Zone: compute.Location, Region: compute.Location}
+    if compute.Zone != "" {
+        z.Zone += "-" + compute.Zone
+    }
+    return z, nil
+}
+
+// metadataGet 送出中繼資料請求並回傳去除空白的內容
+func metadataGet(ctx context.Context, method, url string, header map[string]string) (string, error) {
+    req, err := http.NewRequestWithContext(ctx, method, url, nil)
+    if err != nil {
+        return "", err
+    }
+    for k, v := range header {
+        req.Header.Set(k, v)
+    }
+    resp, err := http.DefaultClient.Do(req)
+    if err != nil {
+        return "", err
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return "", fmt.Errorf("%s %s: %s", method, url, resp.Status)
+    }
+    b, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+    if err != nil {
+        return "", err
+    }
+    return strings.TrimSpace(string(b)), nil
+}
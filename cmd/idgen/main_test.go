@@ -0,0 +1,87 @@
+package main
+
+import (
+    "bufio"
+    "bytes"
+    "encoding/json"
+    "strings"
+    "testing"
+
+    "github.com/pascal910107/idgen"
+)
+
+func TestRunGen(t *testing.T) {
+    var out bytes.Buffer
+    if err := runGen([]string{"-n", "3", "-region", "1", "-node", "42", "-format", "uuid"}, &out); err != nil {
+        t.Fatal(err)
+    }
+    lines := strings.Fields(out.String())
+    if len(lines) != 3 {
+        t.Fatalf("gen printed %d ids, want 3: %q", len(lines), out.String())
+    }
+    var prev idgen.ID
+    for _, s := range lines {
+        id, err := idgen.Parse(s)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if len(s) != 36 || id.Region() != 1 || id.Node() != 42 || id.Compare(prev) <= 0 {
+            t.Fatalf("unexpected id %q after %s", s, prev)
+        }
+        prev = id
+    }
+
+    for _, args := range [][]string{{"-format", "binary"}, {"-format", "nope"}, {"-node", "70000"}} {
+        if err := runGen(args, &out); err == nil {
+            t.Fatalf("gen %v succeeded", args)
+        }
+    }
+}
+
+func TestRunDecode(t *testing.T) {
+    id := idgen.FromParts(1, 123456789, 2, 3, 4)
+    var out bytes.Buffer
+    if err := runDecode([]string{id.Hex(), id.UUIDString()}, &out); err != nil {
+        t.Fatal(err)
+    }
+    dec := json.NewDecoder(&out)
+    for range 2 {
+        var e idgen.Explanation
+        if err := dec.Decode(&e); err != nil {
+            t.Fatal(err)
+        }
+        if e.ID != id.Hex() || e.Epoch != 1 || e.TimestampMillis != 123456789 || e.Region != 2 || e.Node != 3 || e.Sequence != 4 {
+            t.Fatalf("decode = %+v", e)
+        }
+    }
+
+    if err := runDecode(nil, &out); err == nil {
+        t.Fatal("decode without ids succeeded")
+    }
+    if err := runDecode([]string{"xyz"}, &out); err == nil {
+        t.Fatal("decode of an invalid id succeeded")
+    }
+}
+
+func TestRunInspect(t *testing.T) {
+    a := idgen.FromParts(0, 1, 1, 2, 0)
+    b := idgen.FromParts(0, 2, 1, 2, 1)
+    log := "req " + a.Hex() + " ok\nnothing here\nparent=" + b.UUIDString() + " child=deadbeef\n"
+
+    var out bytes.Buffer
+    if err := runInspect(nil, strings.NewReader(log), &out); err != nil {
+        t.Fatal(err)
+    }
+    var got []string
+    sc := bufio.NewScanner(&out)
+    for sc.Scan() {
+        var e idgen.Explanation
+        if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+            t.Fatal(err)
+        }
+        got = append(got, e.ID)
+    }
+    if len(got) != 2 || got[0] != a.Hex() || got[1] != b.Hex() {
+        t.Fatalf("inspect found %v, want [%s %s]", got, a, b)
+    }
+}
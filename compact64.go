@@ -0,0 +1,239 @@
+package idgen
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "strconv"
+    "sync"
+    "time"
+)
+
+// ------------- 64-bit 精簡 ID ------------- //
+
+// ID64 為 Snowflake 風格的 64-bit ID，最高位固定為 0，可直接存入有號 BIGINT 欄位
+// 欄位配置由產生它的 Compact64 的 SnowflakeLayout 決定
+//
+// 與 128-bit ID 互轉：
+//   - ID64 → ID：ToID(layout) (等同 FromSnowflake)，datacenter/worker 對應 region/node，epoch 為 0
+//   - ID → ID64：FromID(id, layout) (等同 ID.ToSnowflake)，捨棄 epoch，欄位超出 layout 時回傳錯誤
+//
+// 兩種格式在同一時間範圍內排序一致，但 ID64 沒有 epoch 欄位，無法以提升 epoch 處理時鐘回撥
+type ID64 int64
+
+// Int64 回傳 ID64 的數值
+func (id ID64) Int64() int64 { return int64(id) }
+
+// String 回傳十進位字串
+func (id ID64) String() string { return strconv.FormatInt(int64(id), 10) }
+
+// ParseID64 解析十進位字串，負數視為不合法
+func ParseID64(s string) (ID64, error) {
+    v, err := strconv.ParseInt(s, 10, 64)
+    if err != nil {
+        return 0, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+    }
+    if v < 0 {
+        return 0, fmt.Errorf("%w: negative id64 %d", ErrInvalidEncoding, v)
+    }
+    return ID64(v), nil
+}
+
+// ToID 依 layout 將 ID64 轉為 128-bit ID
+func (id ID64) ToID(layout SnowflakeLayout) (ID, error) {
+    return FromSnowflake(int64(id), layout)
+}
+
+// FromID 依 layout 將 128-bit ID 轉為 ID64 (有損)
+func FromID(id ID, layout SnowflakeLayout) (ID64, error) {
+    v, err := id.ToSnowflake(layout)
+    return ID64(v), err
+}
+
+// DecodedID64 為 ID64 各欄位解碼後的結構化表示
+type DecodedID64 struct {
+    Time       time.Time
+    Datacenter uint16
+    Worker     uint16
+    Sequence   uint16
+}
+
+// Compact64 產生 ID64，行為與 Generator 相同但依 SnowflakeLayout 配置位元：
+// region 對應 datacenter、node 對應 worker，時間戳相對 layout.Epoch
+//
+// 沒有 epoch 欄位可提升，時鐘回撥超過 RollbackPolicy 的等待上限時一律回傳 ErrClockRollback；
+// 不支援 StateStore 相關選項，也不支援 WithJournal、WithAudit、WithRandomSequenceStart 與 WithFencingTokens
+type Compact64 struct {
+    base   *Generator // 持有時鐘、指標等設定與 NodeIDProvider 資源
+    layout SnowflakeLayout
+
+    mu         sync.Mutex
+    lastMillis uint64
+    lastClock  uint64
+    sequence   uint64
+    closed     bool
+}
+
+// NewCompact64 以 layout 與 New 相同的選項建立 Compact64
+// region 與 node 必須能放入 layout 的 datacenter 與 worker 位元數
+func NewCompact64(layout SnowflakeLayout, opts ...Option) (*Compact64, error) {
+    if err := layout.validate(); err != nil {
+        return nil, err
+    }
+    g, err := New(opts...)
+    if err != nil {
+        return nil, err
+    }
+    err = g.requireDefaults("Compact64")
+    if err == nil {
+        err = g.requireUntracked("Compact64")
+    }
+    switch {
+    case err != nil:
+    case g.store != nil || g.epochStore != nil:
+        err = errors.New("idgen: compact64 generator does not support state store")
+    case uint64(g.regionID) > mask64(layout.DatacenterBits):
+        err = fmt.Errorf("%w: %d does not fit %d datacenter bits", ErrRegionOutOfRange, g.regionID, layout.DatacenterBits)
+    case uint64(g.nodeID) > mask64(layout.WorkerBits):
+        err = fmt.Errorf("%w: %d does not fit %d worker bits", ErrNodeOutOfRange, g.nodeID, layout.WorkerBits)
+    }
+    if err != nil {
+        g.Release()
+        return nil, err
+    }
+    return &Compact64{base: g, layout: layout}, nil
+}
+
+// Next 產生下一個唯一且遞增的 ID64 (thread‑safe)
+func (c *Compact64) Next() (ID64, error) {
+    return c.NextContext(context.Background())
+}
+
+// NextContext 同 Next，但等待時遵守 ctx 的取消與期限
+func (c *Compact64) NextContext(ctx context.Context) (ID64, error) {
+    if err := ctx.Err(); err != nil {
+        return 0, err
+    }
+
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if c.closed {
+        return 0, ErrGeneratorClosed
+    }
+
+    g := c.base
+    now, err := c.currentMillis()
+    if err != nil {
+        return 0, err
+    }
+
+    if now < c.lastClock {
+        g.metrics.IncCounter(MetricClockRollbacks, 1)
+        drift := time.Duration(c.lastClock-now) * time.Millisecond
+        g.reportRollback(drift)
+        if drift <= g.rollback.maxWait {
+            start := g.clock.Now()
+            err := g.sleep(ctx, drift)
+            g.metrics.ObserveDuration(MetricWaitDuration, g.clock.Now().Sub(start))
+            if err != nil {
+                return 0, err
+            }
+            if now, err = c.currentMillis(); err != nil {
+                return 0, err
+            }
+        }
+        if now < c.lastClock {
+            return 0, fmt.Errorf("%w by %v", ErrClockRollback, time.Duration(c.lastClock-now)*time.Millisecond)
+        }
+    }
+
+    clock := now
+    if now <= c.lastMillis {
+        now = c.lastMillis
+        if c.sequence < mask64(c.layout.SequenceBits) {
+            c.sequence++
+        } else {
+            g.metrics.IncCounter(MetricSequenceExhausted, 1)
+            g.reportExhausted(now)
+            switch g.seqPolicy {
+            case SequenceFail:
+                return 0, fmt.Errorf("%w at %d", ErrSequenceExhausted, now)
+            case SequenceBorrow:
+                now++
+            default:
+                start := g.clock.Now()
+                for now <= c.lastMillis {
+                    if err := g.waitTick(ctx, time.Millisecond); err != nil {
+                        g.metrics.ObserveDuration(MetricWaitDuration, g.clock.Now().Sub(start))
+                        return 0, err
+                    }
+                    if now, err = c.currentMillis(); err != nil {
+                        return 0, err
+                    }
+                }
+                g.metrics.ObserveDuration(MetricWaitDuration, g.clock.Now().Sub(start))
+                clock = now
+            }
+            c.sequence = 0
+        }
+    } else {
+        c.sequence = 0
+    }
+    if now > mask64(c.layout.TimestampBits) {
+        return 0, fmt.Errorf("idgen: timestamp %d overflows %d-bit snowflake layout", now, c.layout.TimestampBits)
+    }
+    c.lastMillis, c.lastClock = now, clock
+    g.metrics.IncCounter(MetricIDsIssued, 1)
+
+    v := now
+    v = v<<c.layout.DatacenterBits | uint64(g.regionID)
+    v = v<<c.layout.WorkerBits | uint64(g.nodeID)
+    v = v<<c.layout.SequenceBits | c.sequence
+    return ID64(v), nil
+}
+
+// Decode 依此 Compact64 的 layout 拆解 id
+func (c *Compact64) Decode(id ID64) DecodedID64 {
+    u := uint64(id)
+    seq := u & mask64(c.layout.SequenceBits)
+    u >>= c.layout.SequenceBits
+    worker := u & mask64(c.layout.WorkerBits)
+    u >>= c.layout.WorkerBits
+    dc := u & mask64(c.layout.DatacenterBits)
+    u >>= c.layout.DatacenterBits
+    return DecodedID64{
+        Time:       time.UnixMilli(c.layout.Epoch + int64(u)).UTC(),
+        Datacenter: uint16(dc),
+        Worker:     uint16(worker),
+        Sequence:   uint16(seq),
+    }
+}
+
+// Layout 回傳此 Compact64 的位元配置
+func (c *Compact64) Layout() SnowflakeLayout {
+    return c.layout
+}
+
+// Close 停止 Compact64：之後的 Next 回傳 ErrGeneratorClosed，並歸還 NodeIDProvider 取得的資源
+// 可重複呼叫；實作 io.Closer
+func (c *Compact64) Close() error {
+    c.mu.Lock()
+    c.closed = true
+    c.mu.Unlock()
+    return c.base.Close()
+}
+
+// Release 歸還建立時由 NodeIDProvider 取得的資源，之後的 Next 同樣回傳 ErrGeneratorClosed
+// Compact64 不持久化狀態，與 Close 相同
+func (c *Compact64) Release() {
+    c.Close()
+}
+
+// currentMillis 回傳相對 layout.Epoch 的毫秒數，早於起算點時回傳錯誤
+func (c *Compact64) currentMillis() (uint64, error) {
+    ms := c.base.clock.Now().UnixMilli() - c.layout.Epoch
+    if ms < 0 {
+        return 0, fmt.Errorf("%w: %d ms before snowflake epoch", ErrClockBeforeEpoch, -ms)
+    }
+    return uint64(ms), nil
+}
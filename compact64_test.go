@@ -0,0 +1,108 @@
+package idgen_test
+
+import (
+    "errors"
+    "path/filepath"
+    "testing"
+    "time"
+
+    "github.com/pascal910107/idgen"
+    "github.com/pascal910107/idgen/testclock"
+)
+
+func TestCompact64LayoutRoundTrip(t *testing.T) {
+    start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+    layouts := []struct {
+        name   string
+        layout idgen.SnowflakeLayout
+    }{
+        {"twitter", idgen.TwitterSnowflake},
+        {"custom", idgen.SnowflakeLayout{Epoch: idgen.CustomEpoch, TimestampBits: 39, DatacenterBits: 8, WorkerBits: 8, SequenceBits: 8}},
+    }
+    for _, tt := range layouts {
+        t.Run(tt.name, func(t *testing.T) {
+            c, err := idgen.NewCompact64(tt.layout, idgen.WithRegionNode(3, 7), idgen.WithClock(testclock.New(start)))
+            if err != nil {
+                t.Fatal(err)
+            }
+            defer c.Release()
+
+            for seq := range uint16(3) {
+                id, err := c.Next()
+                if err != nil {
+                    t.Fatal(err)
+                }
+                want := idgen.DecodedID64{Time: start, Datacenter: 3, Worker: 7, Sequence: seq}
+                if got := c.Decode(id); got != want {
+                    t.Fatalf("Decode(%d) = %+v, want %+v", id, got, want)
+                }
+                if p, err := idgen.ParseID64(id.String()); err != nil || p != id {
+                    t.Fatalf("ParseID64(%s) = %d, %v", id, p, err)
+                }
+                full, err := id.ToID(tt.layout)
+                if err != nil {
+                    t.Fatal(err)
+                }
+                if back, err := idgen.FromID(full, tt.layout); err != nil || back != id {
+                    t.Fatalf("FromID(ToID(%d)) = %d, %v", id, back, err)
+                }
+            }
+        })
+    }
+}
+
+func TestCompact64SequenceExhausted(t *testing.T) {
+    layout := idgen.SnowflakeLayout{Epoch: idgen.CustomEpoch, TimestampBits: 49, DatacenterBits: 5, WorkerBits: 5, SequenceBits: 4}
+    c, err := idgen.NewCompact64(layout, idgen.WithSequencePolicy(idgen.SequenceFail),
+        idgen.WithClock(testclock.New(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer c.Release()
+
+    for i := range 16 {
+        if _, err := c.Next(); err != nil {
+            t.Fatalf("id %d: %v", i, err)
+        }
+    }
+    if _, err := c.Next(); !errors.Is(err, idgen.ErrSequenceExhausted) {
+        t.Fatalf("Next after 16 ids error = %v, want ErrSequenceExhausted", err)
+    }
+}
+
+func TestNewCompact64RejectsUnsupportedOptions(t *testing.T) {
+    state := filepath.Join(t.TempDir(), "state.json")
+    tests := []struct {
+        name string
+        opt  idgen.Option
+    }{
+        {"state store", idgen.WithStateStore(idgen.NewFileStateStore(state))},
+        {"journal", idgen.WithJournal(idgen.NewJournalWriter(nil))},
+        {"audit", idgen.WithAudit(idgen.NewJSONAuditSink(nil))},
+        {"random sequence start", idgen.WithRandomSequenceStart()},
+        {"fencing tokens", idgen.WithFencingTokens()},
+        {"entropy bits", idgen.WithEntropyBits(4)},
+        {"region too wide", idgen.WithRegion(32)},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if c, err := idgen.NewCompact64(idgen.TwitterSnowflake, tt.opt); err == nil {
+                c.Release()
+                t.Fatalf("NewCompact64 accepted %s", tt.name)
+            }
+        })
+    }
+}
+
+func TestCompact64Closed(t *testing.T) {
+    c, err := idgen.NewCompact64(idgen.TwitterSnowflake)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if err := c.Close(); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := c.Next(); !errors.Is(err, idgen.ErrGeneratorClosed) {
+        t.Fatalf("Next after Close error = %v, want ErrGeneratorClosed", err)
+    }
+}
@@ -0,0 +1,134 @@
+package idgen
+
+import (
+    "fmt"
+    "os"
+    "strconv"
+    "time"
+)
+
+// ------------- 設定結構 ------------- //
+
+// 環境變數名稱
+const (
+    EnvRegion    = "IDGEN_REGION"     // 區域 ID，未設定時為 0
+    EnvNode      = "IDGEN_NODE"       // 節點 ID，必填
+    EnvEpoch     = "IDGEN_EPOCH"      // 時間戳起算點，RFC 3339 時間或 Unix 毫秒，未設定時使用 CustomEpoch
+    EnvStateFile = "IDGEN_STATE_FILE" // FileStateStore 的路徑，未設定時不持久化
+)
+
+// Config 以純資料描述 Generator 的設定，零值欄位代表使用預設值
+// 適合由環境變數或設定檔填入後交給 NewFromConfig
+type Config struct {
+    Region uint32
+    Node   uint32
+
+    // CustomEpoch 為時間戳起算點，零值代表使用 CustomEpoch
+    CustomEpoch time.Time
+    // Layout 為位元配置，零值代表使用 DefaultLayout
+    Layout Layout
+    // Rollback 為時鐘回撥策略，nil 代表使用 DefaultRollbackPolicy
+    Rollback *RollbackPolicy
+    // SequencePolicy 為序列號用盡時的處理方式，零值為 SequenceWait
+    SequencePolicy SequencePolicy
+    // StateFile 不為空時以 FileStateStore 持久化狀態
+    StateFile string
+}
+
+// Validate 檢查設定是否合法，不建立 Generator
+func (c Config) Validate() error {
+    layout := DefaultLayout
+    if c.Layout != (Layout{}) {
+        if err := c.Layout.Validate(); err != nil {
+            return err
+        }
+        layout = c.Layout
+    }
+    if max := layout.MaxRegion(); c.Region > max {
+        return fmt.Errorf("%w: %d not in 0-%d", ErrRegionOutOfRange, c.Region, max)
+    }
+    if max := layout.MaxNode(); c.Node > max {
+        return fmt.Errorf("%w: %d not in 0-%d", ErrNodeOutOfRange, c.Node, max)
+    }
+    if !c.CustomEpoch.IsZero() && c.CustomEpoch.After(time.Now()) {
+        return fmt.Errorf("%w: custom epoch %s is in the future", ErrClockBeforeEpoch, c.CustomEpoch.Format(time.RFC3339))
+    }
+    switch c.SequencePolicy {
+    case SequenceWait, SequenceFail, SequenceBorrow:
+    default:
+        return fmt.Errorf("idgen: unknown sequence policy %d", c.SequencePolicy)
+    }
+    return nil
+}
+
+// Options 回傳與設定等價的選項
+func (c Config) Options() []Option {
+    opts := []Option{WithRegionNode(c.Region, c.Node), WithSequencePolicy(c.SequencePolicy)}
+    if !c.CustomEpoch.IsZero() {
+        opts = append(opts, WithCustomEpoch(c.CustomEpoch))
+    }
+    if c.Layout != (Layout{}) {
+        opts = append(opts, WithLayout(c.Layout))
+    }
+    if c.Rollback != nil {
+        opts = append(opts, WithRollbackPolicy(*c.Rollback))
+    }
+    if c.StateFile != "" {
+        opts = append(opts, WithStateStore(NewFileStateStore(c.StateFile)))
+    }
+    return opts
+}
+
+// NewFromConfig 驗證 c 後建立 Generator，opts 於設定之後套用，可覆寫設定值
+func NewFromConfig(c Config, opts ...Option) (*Generator, error) {
+    if err := c.Validate(); err != nil {
+        return nil, err
+    }
+    return New(append(c.Options(), opts...)...)
+}
+
+// ConfigFromEnv 自 IDGEN_REGION、IDGEN_NODE、IDGEN_EPOCH 與 IDGEN_STATE_FILE 讀取設定
+// IDGEN_NODE 未設定時回傳錯誤，避免多個副本意外共用預設節點 ID
+func ConfigFromEnv() (Config, error) {
+    var c Config
+    if v, ok := os.LookupEnv(EnvRegion); ok {
+        n, err := strconv.ParseUint(v, 10, 32)
+        if err != nil {
+            return c, fmt.Errorf("%w: %s=%q", ErrRegionOutOfRange, EnvRegion, v)
+        }
+        c.Region = uint32(n)
+    }
+    v, ok := os.LookupEnv(EnvNode)
+    if !ok {
+        return c, fmt.Errorf("idgen: environment variable %s not set", EnvNode)
+    }
+    n, err := strconv.ParseUint(v, 10, 32)
+    if err != nil {
+        return c, fmt.Errorf("%w: %s=%q", ErrNodeOutOfRange, EnvNode, v)
+    }
+    c.Node = uint32(n)
+    if v, ok := os.LookupEnv(EnvEpoch); ok {
+        if c.CustomEpoch, err = parseEpoch(v); err != nil {
+            return c, fmt.Errorf("idgen: %s=%q: %w", EnvEpoch, v, err)
+        }
+    }
+    c.StateFile = os.Getenv(EnvStateFile)
+    return c, nil
+}
+
+// NewGeneratorFromEnv 以 ConfigFromEnv 讀取的設定建立 Generator，opts 於設定之後套用
+func NewGeneratorFromEnv(opts ...Option) (*Generator, error) {
+    c, err := ConfigFromEnv()
+    if err != nil {
+        return nil, err
+    }
+    return NewFromConfig(c, opts...)
+}
+
+// parseEpoch 接受 RFC 3339 時間或 Unix 毫秒
+func parseEpoch(s string) (time.Time, error) {
+    if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+        return time.UnixMilli(ms).UTC(), nil
+    }
+    return time.Parse(time.RFC3339, s)
+}
@@ -0,0 +1,171 @@
+// Package config 自 YAML 或 TOML 設定檔載入 Generator 設定，供以設定管理工具維護參數的部署使用
+//
+//    # idgen.yaml
+//    region: 1
+//    node: 42
+//    epoch: 2025-01-01T00:00:00Z
+//    layout: {epoch_bits: 16, timestamp_bits: 64, region_bits: 8, node_bits: 24, sequence_bits: 16}
+//    rollback: {policy: wait, max_wait: 10ms}
+//    sequence: wait
+//    state_file: /var/lib/idgen/state.json
+//
+//    g, err := config.NewGenerator("/etc/idgen.yaml")
+//
+// 未知的欄位、不合法的列舉值與超出範圍的 ID 皆視為錯誤，避免設定打錯字而被默默忽略
+package config
+
+import (
+    "bytes"
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "time"
+
+    "github.com/BurntSushi/toml"
+    "github.com/pascal910107/idgen"
+    "gopkg.in/yaml.v3"
+)
+
+// ErrInvalidConfig 表示設定檔無法解析或未通過驗證
+var ErrInvalidConfig = errors.New("config: invalid idgen config")
+
+// Format 為設定檔格式
+type Format int
+
+const (
+    // YAML 格式 (.yaml、.yml)
+    YAML Format = iota
+    // TOML 格式 (.toml)
+    TOML
+)
+
+// File 為設定檔的結構，欄位名稱即設定檔中的鍵
+type File struct {
+    Region    uint32    `yaml:"region" toml:"region"`
+    Node      *uint32   `yaml:"node" toml:"node"` // 必填
+    Epoch     time.Time `yaml:"epoch" toml:"epoch"`
+    Layout    *Layout   `yaml:"layout" toml:"layout"`
+    Rollback  *Rollback `yaml:"rollback" toml:"rollback"`
+    Sequence  string    `yaml:"sequence" toml:"sequence"` // wait (預設)、fail 或 borrow
+    StateFile string    `yaml:"state_file" toml:"state_file"`
+}
+
+// Layout 對應 idgen.Layout
+type Layout struct {
+    EpochBits     uint8 `yaml:"epoch_bits" toml:"epoch_bits"`
+    TimestampBits uint8 `yaml:"timestamp_bits" toml:"timestamp_bits"`
+    RegionBits    uint8 `yaml:"region_bits" toml:"region_bits"`
+    NodeBits      uint8 `yaml:"node_bits" toml:"node_bits"`
+    SequenceBits  uint8 `yaml:"sequence_bits" toml:"sequence_bits"`
+}
+
+// Rollback 對應 idgen.RollbackPolicy
+type Rollback struct {
+    Policy  string        `yaml:"policy" toml:"policy"`     // wait (預設)、bump 或 error
+    MaxWait time.Duration `yaml:"max_wait" toml:"max_wait"` // policy 為 wait 時的等待上限，預設 5ms
+}
+
+// Load 讀取並驗證 path，格式依副檔名判斷
+func Load(path string) (idgen.Config, error) {
+    var format Format
+    switch strings.ToLower(filepath.Ext(path)) {
+    case ".yaml", ".yml":
+        format = YAML
+    case ".toml":
+        format = TOML
+    default:
+        return idgen.Config{}, fmt.Errorf("%w: unknown file extension %q", ErrInvalidConfig, filepath.Ext(path))
+    }
+    data, err := os.ReadFile(path)
+    if err != nil {
+        return idgen.Config{}, err
+    }
+    c, err := Parse(data, format)
+    if err != nil {
+        return c, fmt.Errorf("%s: %w", path, err)
+    }
+    return c, nil
+}
+
+// Parse 解析並驗證設定內容
+func Parse(data []byte, format Format) (idgen.Config, error) {
+    var f File
+    switch format {
+    case YAML:
+        dec := yaml.NewDecoder(bytes.NewReader(data))
+        dec.KnownFields(true)
+        if err := dec.Decode(&f); err != nil {
+            return idgen.Config{}, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+        }
+    case TOML:
+        md, err := toml.NewDecoder(bytes.NewReader(data)).Decode(&f)
+        if err != nil {
+            return idgen.Config{}, fmt.Errorf("%w: %v", ErrInvalidConfig, err)
+        }
+        if keys := md.Undecoded(); len(keys) > 0 {
+            return idgen.Config{}, fmt.Errorf("%w: unknown key %q", ErrInvalidConfig, keys[0].String())
+        }
+    default:
+        return idgen.Config{}, fmt.Errorf("%w: unknown format %d", ErrInvalidConfig, format)
+    }
+    return f.Config()
+}
+
+// Config 將 File 轉為 idgen.Config 並驗證
+func (f File) Config() (idgen.Config, error) {
+    if f.Node == nil {
+        return idgen.Config{}, fmt.Errorf("%w: node is required", ErrInvalidConfig)
+    }
+    c := idgen.Config{Region: f.Region, Node: *f.Node, CustomEpoch: f.Epoch, StateFile: f.StateFile}
+    if f.Layout != nil {
+        c.Layout = idgen.Layout(*f.Layout)
+    }
+
+    switch f.Sequence {
+    case "", "wait":
+        c.SequencePolicy = idgen.SequenceWait
+    case "fail":
+        c.SequencePolicy = idgen.SequenceFail
+    case "borrow":
+        c.SequencePolicy = idgen.SequenceBorrow
+    default:
+        return c, fmt.Errorf("%w: unknown sequence policy %q", ErrInvalidConfig, f.Sequence)
+    }
+
+    if r := f.Rollback; r != nil {
+        var p idgen.RollbackPolicy
+        switch r.Policy {
+        case "", "wait":
+            p = idgen.DefaultRollbackPolicy
+            if r.MaxWait > 0 {
+                p = idgen.WaitUpTo(r.MaxWait)
+            }
+        case "bump":
+            p = idgen.BumpEpoch()
+        case "error":
+            p = idgen.ReturnError()
+        default:
+            return c, fmt.Errorf("%w: unknown rollback policy %q", ErrInvalidConfig, r.Policy)
+        }
+        if r.MaxWait < 0 || (r.MaxWait > 0 && r.Policy != "" && r.Policy != "wait") {
+            return c, fmt.Errorf("%w: max_wait applies only to the wait rollback policy", ErrInvalidConfig)
+        }
+        c.Rollback = &p
+    }
+
+    if err := c.Validate(); err != nil {
+        return c, fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+    }
+    return c, nil
+}
+
+// NewGenerator 以 Load 讀取 path 後建立 Generator，opts 於設定之後套用
+func NewGenerator(path string, opts ...idgen.Option) (*idgen.Generator, error) {
+    c, err := Load(path)
+    if err != nil {
+        return nil, err
+    }
+    return idgen.NewFromConfig(c, opts...)
+}
@@ -0,0 +1,122 @@
+package config_test
+
+import (
+    "errors"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+
+    "github.com/pascal910107/idgen"
+    "github.com/pascal910107/idgen/config"
+)
+
+const yamlConfig = `
+region: 1
+node: 42
+epoch: 2025-01-01T00:00:00Z
+layout: {epoch_bits: 16, timestamp_bits: 64, region_bits: 8, node_bits: 24, sequence_bits: 16}
+rollback: {policy: wait, max_wait: 10ms}
+sequence: borrow
+`
+
+const tomlConfig = `
+region = 1
+node = 42
+epoch = 2025-01-01T00:00:00Z
+sequence = "borrow"
+
+[layout]
+epoch_bits = 16
+timestamp_bits = 64
+region_bits = 8
+node_bits = 24
+sequence_bits = 16
+
+[rollback]
+policy = "wait"
+max_wait = "10ms"
+`
+
+func TestParse(t *testing.T) {
+    want := idgen.Config{
+        Region:         1,
+        Node:           42,
+        CustomEpoch:    time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+        Layout:         idgen.Layout{EpochBits: 16, TimestampBits: 64, RegionBits: 8, NodeBits: 24, SequenceBits: 16},
+        SequencePolicy: idgen.SequenceBorrow,
+    }
+    tests := []struct {
+        name   string
+        data   string
+        format config.Format
+    }{
+        {"yaml", yamlConfig, config.YAML},
+        {"toml", tomlConfig, config.TOML},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            c, err := config.Parse([]byte(tt.data), tt.format)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if c.Rollback == nil || *c.Rollback != idgen.WaitUpTo(10*time.Millisecond) {
+                t.Fatalf("Rollback = %v, want WaitUpTo(10ms)", c.Rollback)
+            }
+            c.Rollback = nil
+            if !c.CustomEpoch.Equal(want.CustomEpoch) {
+                t.Fatalf("CustomEpoch = %v, want %v", c.CustomEpoch, want.CustomEpoch)
+            }
+            c.CustomEpoch = want.CustomEpoch
+            if c != want {
+                t.Fatalf("Parse = %+v, want %+v", c, want)
+            }
+        })
+    }
+}
+
+func TestParseRejects(t *testing.T) {
+    tests := []struct {
+        name string
+        data string
+    }{
+        {"missing node", "region: 1\n"},
+        {"unknown key", "node: 1\nnodes: 2\n"},
+        {"unknown sequence policy", "node: 1\nsequence: spin\n"},
+        {"unknown rollback policy", "node: 1\nrollback: {policy: ignore}\n"},
+        {"max wait with bump", "node: 1\nrollback: {policy: bump, max_wait: 5ms}\n"},
+        {"node out of range", "node: 70000\n"},
+        {"bad layout", "node: 1\nlayout: {epoch_bits: 16, timestamp_bits: 64, region_bits: 16, node_bits: 16, sequence_bits: 8}\n"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if _, err := config.Parse([]byte(tt.data), config.YAML); !errors.Is(err, config.ErrInvalidConfig) {
+                t.Fatalf("Parse error = %v, want ErrInvalidConfig", err)
+            }
+        })
+    }
+}
+
+func TestNewGenerator(t *testing.T) {
+    dir := t.TempDir()
+    path := filepath.Join(dir, "idgen.yaml")
+    if err := os.WriteFile(path, []byte(yamlConfig), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    g, err := config.NewGenerator(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer g.Release()
+    id, err := g.Next()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if f := g.Decode(id); f.Region != 1 || f.Node != 42 {
+        t.Fatalf("Decode = %+v, want region 1 node 42", f)
+    }
+
+    if _, err := config.Load(filepath.Join(dir, "idgen.json")); !errors.Is(err, config.ErrInvalidConfig) {
+        t.Fatalf("Load(.json) error = %v, want ErrInvalidConfig", err)
+    }
+}
@@ -0,0 +1,242 @@
+package coordination
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "net"
+    "sync"
+    "time"
+
+    "github.com/pascal910107/idgen"
+)
+
+// ------------- 節點 ID 碰撞偵測 ------------- //
+
+// Announcement 為各程序定期廣播的節點宣告
+// Instance 為每次 Acquire 隨機產生的識別碼，用來區分同一 (region, node) 的不同持有者
+type Announcement struct {
+    Region   uint16 `json:"region"`
+    Node     uint16 `json:"node"`
+    Instance string `json:"instance"`
+    Owner    string `json:"owner,omitempty"`
+}
+
+// Channel 為傳遞 Announcement 的通道，可替換為 UDP 群播以外的實作 (例如訊息佇列)
+type Channel interface {
+    // Publish 送出一則宣告
+    Publish(ctx context.Context, a Announcement) error
+    // Subscribe 開始接收宣告並以 fn 處理 (包含自己送出的)，直到呼叫回傳的 stop
+    Subscribe(fn func(Announcement)) (stop func(), err error)
+}
+
+// CollisionDetector 包裝 NodeIDProvider：取得節點 ID 後持續廣播自己的 (region, node)，
+// 並監聽其他程序的宣告，發現另一個存活的程序宣告相同的 (region, node) 時呼叫 OnCollision
+//
+// 用於偵測設定錯誤 (例如兩個副本使用相同的環境變數)；外部協調服務已保證唯一時不需要
+type CollisionDetector struct {
+    Provider idgen.NodeIDProvider
+    Channel  Channel
+    Interval time.Duration // 廣播間隔，預設 1 秒
+    Owner    string        // 宣告中的持有者描述，預設為 hostname:pid
+
+    // Probe 大於 0 時，Acquire 會先監聽 Probe 時間；期間發現碰撞即歸還節點並回傳 ErrNodeCollision
+    // 建議設為 Interval 的兩倍以上
+    Probe time.Duration
+
+    // OnCollision 在取得節點 ID 之後發現碰撞時被呼叫，每個衝突的 Instance 只呼叫一次
+    // 此時兩個程序可能產生重複 ID，應停止產生 ID 或結束程序
+    OnCollision func(self, other Announcement)
+}
+
+// Acquire 實作 idgen.NodeIDProvider
+func (d *CollisionDetector) Acquire(ctx context.Context) (region, node uint16, release func(), err error) {
+    region, node, inner, err := d.Provider.Acquire(ctx)
+    if err != nil {
+        return 0, 0, nil, err
+    }
+    self := Announcement{Region: region, Node: node, Owner: d.Owner}
+    if self.Owner == "" {
+        self.Owner = defaultOwner()
+    }
+    var b [8]byte
+    if _, err := rand.Read(b[:]); err != nil {
+        return d.fail(inner, err)
+    }
+    self.Instance = hex.EncodeToString(b[:])
+
+    var (
+        mu       sync.Mutex
+        reported = map[string]bool{}
+        probing  = d.Probe > 0
+        probeHit *Announcement
+    )
+    unsubscribe, err := d.Channel.Subscribe(func(a Announcement) {
+        if a.Region != self.Region || a.Node != self.Node || a.Instance == self.Instance {
+            return
+        }
+        mu.Lock()
+        if probing {
+            if probeHit == nil {
+                probeHit = &a
+            }
+            mu.Unlock()
+            return
+        }
+        first := !reported[a.Instance]
+        reported[a.Instance] = true
+        mu.Unlock()
+        if first && d.OnCollision != nil {
+            d.OnCollision(self, a)
+        }
+    })
+    if err != nil {
+        return d.fail(inner, fmt.Errorf("coordination: subscribe announcements: %w", err))
+    }
+
+    hbCtx, stop := context.WithCancel(context.Background())
+    done := make(chan struct{})
+    go d.announce(hbCtx, done, self)
+
+    var once sync.Once
+    release = func() {
+        once.Do(func() {
+            stop()
+            <-done
+            unsubscribe()
+            if inner != nil {
+                inner()
+            }
+        })
+    }
+
+    if probing {
+        t := time.NewTimer(d.Probe)
+        select {
+        case <-ctx.Done():
+            t.Stop()
+            release()
+            return 0, 0, nil, ctx.Err()
+        case <-t.C:
+        }
+        mu.Lock()
+        probing = false
+        hit := probeHit
+        mu.Unlock()
+        if hit != nil {
+            release()
+            return 0, 0, nil, fmt.Errorf("%w: region %d node %d also held by %s (%s)", ErrNodeCollision, region, node, hit.Owner, hit.Instance)
+        }
+    }
+    return region, node, release, nil
+}
+
+// announce 立即廣播一次，之後每 Interval 廣播一次直到 ctx 結束
+// 送出失敗視為暫時性錯誤，於下次重試
+func (d *CollisionDetector) announce(ctx context.Context, done chan<- struct{}, self Announcement) {
+    defer close(done)
+
+    interval := d.Interval
+    if interval <= 0 {
+        interval = time.Second
+    }
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+    for {
+        d.Channel.Publish(ctx, self)
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+    }
+}
+
+func (d *CollisionDetector) fail(release func(), err error) (uint16, uint16, func(), error) {
+    if release != nil {
+        release()
+    }
+    return 0, 0, nil, err
+}
+
+// ------------- UDP 群播 ------------- //
+
+// DefaultMulticastGroup 為 UDPChannel 預設使用的群播位址
+const DefaultMulticastGroup = "239.255.77.77:7946"
+
+// UDPChannel 以 UDP 群播傳遞宣告，適用同一網段內的程序 (同一主機的多個程序可共用同一埠)
+type UDPChannel struct {
+    Group     string         // 群播位址，預設 DefaultMulticastGroup
+    Interface *net.Interface // 監聽的網路介面，nil 代表由系統選擇
+}
+
+// Publish 實作 Channel
+func (c UDPChannel) Publish(ctx context.Context, a Announcement) error {
+    addr, err := net.ResolveUDPAddr("udp4", c.group())
+    if err != nil {
+        return err
+    }
+    msg, err := json.Marshal(a)
+    if err != nil {
+        return err
+    }
+    conn, err := net.DialUDP("udp4", nil, addr)
+    if err != nil {
+        return err
+    }
+    defer conn.Close()
+    if deadline, ok := ctx.Deadline(); ok {
+        conn.SetWriteDeadline(deadline)
+    }
+    _, err = conn.Write(msg)
+    return err
+}
+
+// Subscribe 實作 Channel；無法解析的封包直接忽略
+func (c UDPChannel) Subscribe(fn func(Announcement)) (func(), error) {
+    addr, err := net.ResolveUDPAddr("udp4", c.group())
+    if err != nil {
+        return nil, err
+    }
+    conn, err := net.ListenMulticastUDP("udp4", c.Interface, addr)
+    if err != nil {
+        return nil, err
+    }
+
+    done := make(chan struct{})
+    go func() {
+        defer close(done)
+        buf := make([]byte, 1500)
+        for {
+            n, _, err := conn.ReadFromUDP(buf)
+            if errors.Is(err, net.ErrClosed) {
+                return
+            }
+            if err != nil {
+                continue
+            }
+            var a Announcement
+            if json.Unmarshal(buf[:n], &a) == nil {
+                fn(a)
+            }
+        }
+    }()
+
+    var once sync.Once
+    return func() {
+        once.Do(func() {
+            conn.Close()
+            <-done
+        })
+    }, nil
+}
+
+func (c UDPChannel) group() string {
+    if c.Group == "" {
+        return DefaultMulticastGroup
+    }
+    return c.Group
+}
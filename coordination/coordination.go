@@ -0,0 +1,36 @@
+// Package coordination 提供透過外部協調服務取得唯一節點 ID 的實作
+// 各實作皆符合 idgen.NodeIDProvider：Acquire 取得 (region, node) 並回傳 release 函式，
+// 程式結束前呼叫 release 即可歸還節點 ID 供其他副本使用
+//
+// Kubernetes 環境可改用 StatefulSetProvider、PodHashProvider 或 ConfigMapAllocator，不需額外基礎設施；
+// CollisionDetector 可包裝任一 NodeIDProvider，於執行期間偵測其他程序是否宣告相同的節點 ID
+package coordination
+
+import (
+    "errors"
+    "os"
+    "strconv"
+
+    "github.com/pascal910107/idgen"
+)
+
+// ErrNoFreeNode 表示指定區域內所有節點 ID 皆已被占用
+var ErrNoFreeNode = errors.New("coordination: no free node id")
+
+// ErrNodeCollision 表示另一個存活的程序宣告了相同的 (region, node)
+var ErrNodeCollision = errors.New("coordination: node id collision")
+
+// defaultOwner 回傳 hostname:pid，作為寫入協調服務的持有者描述
+func defaultOwner() string {
+    host, _ := os.Hostname()
+    return host + ":" + strconv.Itoa(os.Getpid())
+}
+
+var (
+    _ idgen.NodeIDProvider = (*EtcdAllocator)(nil)
+    _ idgen.NodeIDProvider = (*RedisAllocator)(nil)
+    _ idgen.NodeIDProvider = (*CollisionDetector)(nil)
+    _ idgen.NodeIDProvider = (*ConfigMapAllocator)(nil)
+    _ idgen.NodeIDProvider = StatefulSetProvider{}
+    _ idgen.NodeIDProvider = PodHashProvider{}
+)
@@ -0,0 +1,139 @@
+package coordination
+
+import (
+    "context"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+
+    clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// EtcdAllocator 以 etcd lease 分配節點 ID
+// 每個節點 ID 對應一個綁定 lease 的 key，持有期間持續 keepalive；
+// 程序異常結束時 lease 於 TTL 後過期，key 自動刪除而釋出節點 ID
+type EtcdAllocator struct {
+    Client  *clientv3.Client
+    Region  uint16
+    Prefix  string        // key 前綴，預設 "/idgen/nodes/"
+    TTL     time.Duration // lease 存活時間，預設 10 秒；etcd 以秒為單位，不足 1 秒以 1 秒計
+    MaxNode uint16        // 可分配的最大節點 ID，0 代表使用 65535
+    Owner   string        // 寫入 key 的值，方便除錯；預設為 hostname:pid
+
+    // OnLost 在 keepalive 中斷 (lease 可能已過期) 時被呼叫
+    // 此時節點 ID 可能已被其他副本取得，應停止產生 ID 或重新 Acquire
+    OnLost func(region, node uint16)
+}
+
+// NewEtcdAllocator 建立在 region 內分配節點 ID 的 EtcdAllocator
+func NewEtcdAllocator(client *clientv3.Client, region uint16) *EtcdAllocator {
+    return &EtcdAllocator{Client: client, Region: region}
+}
+
+// Acquire 取得一個尚未被占用的節點 ID，並於背景持續續約
+// release 會停止續約並撤銷 lease，使節點 ID 立即可被重用
+func (a *EtcdAllocator) Acquire(ctx context.Context) (region, node uint16, release func(), err error) {
+    prefix := a.regionPrefix()
+
+    lease, err := a.Client.Grant(ctx, int64(a.ttl()/time.Second))
+    if err != nil {
+        return 0, 0, nil, fmt.Errorf("coordination: grant lease: %w", err)
+    }
+    revoke := func() {
+        rctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+        a.Client.Revoke(rctx, lease.ID)
+    }
+
+    // 先列出已占用的節點，只對空位嘗試寫入以減少往返
+    resp, err := a.Client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly())
+    if err != nil {
+        revoke()
+        return 0, 0, nil, fmt.Errorf("coordination: list nodes: %w", err)
+    }
+    taken := make(map[uint16]bool, len(resp.Kvs))
+    for _, kv := range resp.Kvs {
+        if n, err := strconv.ParseUint(strings.TrimPrefix(string(kv.Key), prefix), 10, 16); err == nil {
+            taken[uint16(n)] = true
+        }
+    }
+
+    for n := 0; n <= int(a.maxNode()); n++ {
+        if taken[uint16(n)] {
+            continue
+        }
+        key := prefix + strconv.Itoa(n)
+        txn, err := a.Client.Txn(ctx).
+            If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+            Then(clientv3.OpPut(key, a.owner(), clientv3.WithLease(lease.ID))).
+            Commit()
+        if err != nil {
+            revoke()
+            return 0, 0, nil, fmt.Errorf("coordination: claim node %d: %w", n, err)
+        }
+        if !txn.Succeeded { // 與其他副本競爭失敗，換下一個
+            continue
+        }
+
+        kaCtx, stop := context.WithCancel(context.Background())
+        ch, err := a.Client.KeepAlive(kaCtx, lease.ID)
+        if err != nil {
+            stop()
+            revoke()
+            return 0, 0, nil, fmt.Errorf("coordination: keepalive: %w", err)
+        }
+        go a.drain(kaCtx, ch, uint16(n))
+
+        release = func() {
+            stop()
+            revoke()
+        }
+        return a.Region, uint16(n), release, nil
+    }
+
+    revoke()
+    return 0, 0, nil, fmt.Errorf("%w in region %d", ErrNoFreeNode, a.Region)
+}
+
+// drain 消耗 keepalive 回應；通道在未被主動停止的情況下關閉即代表續約中斷
+func (a *EtcdAllocator) drain(ctx context.Context, ch <-chan *clientv3.LeaseKeepAliveResponse, node uint16) {
+    for range ch {
+    }
+    if ctx.Err() == nil && a.OnLost != nil {
+        a.OnLost(a.Region, node)
+    }
+}
+
+func (a *EtcdAllocator) regionPrefix() string {
+    prefix := a.Prefix
+    if prefix == "" {
+        prefix = "/idgen/nodes/"
+    }
+    return prefix + strconv.Itoa(int(a.Region)) + "/"
+}
+
+func (a *EtcdAllocator) ttl() time.Duration {
+    switch {
+    case a.TTL <= 0:
+        return 10 * time.Second
+    case a.TTL < time.Second:
+        return time.Second
+    default:
+        return a.TTL
+    }
+}
+
+func (a *EtcdAllocator) maxNode() uint16 {
+    if a.MaxNode == 0 {
+        return 65535
+    }
+    return a.MaxNode
+}
+
+func (a *EtcdAllocator) owner() string {
+    if a.Owner != "" {
+        return a.Owner
+    }
+    return defaultOwner()
+}
@@ -0,0 +1,350 @@
+package coordination
+
+import (
+    "bytes"
+    "context"
+    "crypto/tls"
+    "crypto/x509"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "hash/fnv"
+    "io"
+    "net"
+    "net/http"
+    "os"
+    "strconv"
+    "strings"
+    "sync"
+    "time"
+)
+
+// ------------- Kubernetes ------------- //
+
+// serviceAccountDir 為 Pod 內 service account 憑證的掛載位置 (測試時替換)
+var serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// podName 回傳 name，未指定時依序使用環境變數 POD_NAME (Downward API) 與主機名稱
+// Kubernetes 預設以 Pod 名稱作為容器的主機名稱
+func podName(name string) (string, error) {
+    if name != "" {
+        return name, nil
+    }
+    if v := os.Getenv("POD_NAME"); v != "" {
+        return v, nil
+    }
+    return os.Hostname()
+}
+
+// StatefulSetProvider 以 StatefulSet 的序號 (Pod 名稱結尾的 -N) 作為節點 ID
+// 序號在 StatefulSet 內唯一且重啟後不變，不需要任何外部協調
+//
+//    env:
+//      - name: POD_NAME
+//        valueFrom: {fieldRef: {fieldPath: metadata.name}}
+type StatefulSetProvider struct {
+    Region  uint16
+    PodName string // 預設讀取 POD_NAME，未設定時使用主機名稱
+    Offset  uint16 // 加到序號上的偏移，讓多個 StatefulSet 共用同一區域時不重疊
+}
+
+// Acquire 實作 idgen.NodeIDProvider
+func (p StatefulSetProvider) Acquire(context.Context) (uint16, uint16, func(), error) {
+    name, err := podName(p.PodName)
+    if err != nil {
+        return 0, 0, nil, err
+    }
+    i := strings.LastIndexByte(name, '-')
+    ordinal, err := strconv.ParseUint(name[i+1:], 10, 16)
+    if i < 0 || err != nil {
+        return 0, 0, nil, fmt.Errorf("coordination: pod name %q has no statefulset ordinal", name)
+    }
+    if ordinal+uint64(p.Offset) > 0xffff {
+        return 0, 0, nil, fmt.Errorf("coordination: ordinal %d + offset %d exceeds 65535", ordinal, p.Offset)
+    }
+    return p.Region, uint16(ordinal) + p.Offset, nil, nil
+}
+
+// PodHashProvider 以 Pod 名稱的雜湊推導節點 ID，適用 Deployment 等沒有固定序號的工作負載
+// 雜湊無法保證唯一：副本數接近 MaxNode 時碰撞機率高，建議搭配 CollisionDetector 或改用 ConfigMapAllocator
+type PodHashProvider struct {
+    Region  uint16
+    PodName string // 預設讀取 POD_NAME，未設定時使用主機名稱
+    MaxNode uint16 // 節點 ID 上限，0 代表使用 65535
+}
+
+// Acquire 實作 idgen.NodeIDProvider
+func (p PodHashProvider) Acquire(context.Context) (uint16, uint16, func(), error) {
+    name, err := podName(p.PodName)
+    if err != nil {
+        return 0, 0, nil, err
+    }
+    h := fnv.New32a()
+    h.Write([]byte(name))
+    limit := uint32(p.MaxNode)
+    if limit == 0 {
+        limit = 0xffff
+    }
+    return p.Region, uint16(h.Sum32() % (limit + 1)), nil, nil
+}
+
+// ConfigMapAllocator 以 ConfigMap 記錄 Pod 與節點 ID 的對應，透過 resourceVersion 樂觀鎖分配
+// 只使用 Pod 內的 service account 呼叫 API Server，不需額外的協調服務；
+// service account 需要該 ConfigMap 的 get、create、update 權限
+//
+// 鍵為 "<region>.<pod>"，值為節點 ID。同名 Pod (例如 StatefulSet) 重新 Acquire 會取回相同節點；
+// release 會刪除對應的鍵，但異常結束的 Pod 不會自動歸還，需由維運清理
+type ConfigMapAllocator struct {
+    Region    uint16
+    Name      string // ConfigMap 名稱，預設 "idgen-nodes"
+    Namespace string // 預設為 Pod 所在的 namespace
+    PodName   string // 預設讀取 POD_NAME，未設定時使用主機名稱
+    MaxNode   uint16 // 可分配的最大節點 ID，0 代表使用 65535
+
+    // APIServer、Token 與 HTTPClient 預設取自 in-cluster 設定
+    APIServer  string
+    Token      string
+    HTTPClient *http.Client
+
+    mu     sync.Mutex
+    client *http.Client // HTTPClient 為 nil 時以叢集 CA 建立一次後重複使用，保留連線
+}
+
+// configMap 為 ConfigMap 中用到的欄位
+type configMap struct {
+    APIVersion string            `json:"apiVersion"`
+    Kind       string            `json:"kind"`
+    Metadata   configMapMeta     `json:"metadata"`
+    Data       map[string]string `json:"data"`
+}
+
+type configMapMeta struct {
+    Name            string `json:"name"`
+    Namespace       string `json:"namespace"`
+    ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+// errConflict 表示寫入時 resourceVersion 已過期或 ConfigMap 已被建立
+var errConflict = errors.New("coordination: configmap conflict")
+
+// Acquire 實作 idgen.NodeIDProvider，衝突時重新讀取後重試
+func (a *ConfigMapAllocator) Acquire(ctx context.Context) (region, node uint16, release func(), err error) {
+    pod, err := podName(a.PodName)
+    if err != nil {
+        return 0, 0, nil, err
+    }
+    key := strconv.Itoa(int(a.Region)) + "." + pod
+
+    err = a.update(ctx, func(data map[string]string) (bool, error) {
+        if v, ok := data[key]; ok {
+            n, err := strconv.ParseUint(v, 10, 16)
+            if err != nil {
+                return false, fmt.Errorf("coordination: configmap entry %s=%q is not a node id", key, v)
+            }
+            node = uint16(n)
+            return false, nil
+        }
+        taken := map[uint64]bool{}
+        prefix := strconv.Itoa(int(a.Region)) + "."
+        for k, v := range data {
+            if n, err := strconv.ParseUint(v, 10, 16); err == nil && strings.HasPrefix(k, prefix) {
+                taken[n] = true
+            }
+        }
+        for n := uint64(0); n <= uint64(a.maxNode()); n++ {
+            if !taken[n] {
+                data[key] = strconv.FormatUint(n, 10)
+                node = uint16(n)
+                return true, nil
+            }
+        }
+        return false, fmt.Errorf("%w in region %d", ErrNoFreeNode, a.Region)
+    })
+    if err != nil {
+        return 0, 0, nil, err
+    }
+
+    release = func() {
+        rctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+        defer cancel()
+        a.update(rctx, func(data map[string]string) (bool, error) {
+            if data[key] != strconv.Itoa(int(node)) {
+                return false, nil
+            }
+            delete(data, key)
+            return true, nil
+        })
+    }
+    return a.Region, node, release, nil
+}
+
+// update 讀取 ConfigMap 並以 fn 修改 data，fn 回傳 true 時寫回；寫入衝突時重新讀取並重試
+func (a *ConfigMapAllocator) update(ctx context.Context, fn func(map[string]string) (bool, error)) error {
+    for {
+        cm, err := a.get(ctx)
+        if err != nil {
+            return err
+        }
+        if cm.Data == nil {
+            cm.Data = map[string]string{}
+        }
+        changed, err := fn(cm.Data)
+        if err != nil || !changed {
+            return err
+        }
+        err = a.put(ctx, cm)
+        if !errors.Is(err, errConflict) {
+            return err
+        }
+        if err := ctx.Err(); err != nil {
+            return err
+        }
+    }
+}
+
+// get 讀取 ConfigMap，不存在時回傳尚未建立 (ResourceVersion 為空) 的空白 ConfigMap
+func (a *ConfigMapAllocator) get(ctx context.Context) (*configMap, error) {
+    ns, err := a.namespace()
+    if err != nil {
+        return nil, err
+    }
+    cm := &configMap{APIVersion: "v1", Kind: "ConfigMap", Metadata: configMapMeta{Name: a.name(), Namespace: ns}}
+    status, err := a.do(ctx, http.MethodGet, a.path(ns, true), nil, cm)
+    if status == http.StatusNotFound {
+        return cm, nil
+    }
+    return cm, err
+}
+
+// put 以 resourceVersion 樂觀鎖寫回 ConfigMap，尚未建立時改為建立
+func (a *ConfigMapAllocator) put(ctx context.Context, cm *configMap) error {
+    method, path := http.MethodPut, a.path(cm.Metadata.Namespace, true)
+    if cm.Metadata.ResourceVersion == "" {
+        method, path = http.MethodPost, a.path(cm.Metadata.Namespace, false)
+    }
+    status, err := a.do(ctx, method, path, cm, nil)
+    if status == http.StatusConflict {
+        return errConflict
+    }
+    return err
+}
+
+// do 送出 API 請求並回傳 HTTP 狀態碼；非 2xx 時回傳錯誤
+func (a *ConfigMapAllocator) do(ctx context.Context, method, path string, in, out any) (int, error) {
+    server, token, client, err := a.config()
+    if err != nil {
+        return 0, err
+    }
+    var body io.Reader
+    if in != nil {
+        b, err := json.Marshal(in)
+        if err != nil {
+            return 0, err
+        }
+        body = bytes.NewReader(b)
+    }
+    req, err := http.NewRequestWithContext(ctx, method, server+path, body)
+    if err != nil {
+        return 0, err
+    }
+    req.Header.Set("Accept", "application/json")
+    req.Header.Set("Content-Type", "application/json")
+    if token != "" {
+        req.Header.Set("Authorization", "Bearer "+token)
+    }
+
+    resp, err := client.Do(req)
+    if err != nil {
+        return 0, fmt.Errorf("coordination: %s configmap: %w", method, err)
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode/100 != 2 {
+        msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+        return resp.StatusCode, fmt.Errorf("coordination: %s configmap: %s: %s", method, resp.Status, bytes.TrimSpace(msg))
+    }
+    if out != nil {
+        if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+            return resp.StatusCode, fmt.Errorf("coordination: decode configmap: %w", err)
+        }
+    }
+    return resp.StatusCode, nil
+}
+
+// config 回傳 API Server 位址、token 與 HTTP client，未指定者取自 in-cluster 設定
+func (a *ConfigMapAllocator) config() (string, string, *http.Client, error) {
+    server, token, client := a.APIServer, a.Token, a.HTTPClient
+    if server == "" {
+        host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+        if host == "" || port == "" {
+            return "", "", nil, errors.New("coordination: not running in a kubernetes cluster and no APIServer set")
+        }
+        server = "https://" + net.JoinHostPort(host, port)
+    }
+    if token == "" {
+        b, err := os.ReadFile(serviceAccountDir + "/token")
+        if err != nil {
+            return "", "", nil, fmt.Errorf("coordination: read service account token: %w", err)
+        }
+        token = strings.TrimSpace(string(b))
+    }
+    if client == nil {
+        var err error
+        if client, err = a.clusterClient(); err != nil {
+            return "", "", nil, err
+        }
+    }
+    return strings.TrimSuffix(server, "/"), token, client, nil
+}
+
+// clusterClient 回傳信任叢集 CA 的 http.Client，第一次成功建立後快取；讀取失敗時下次呼叫會重試
+func (a *ConfigMapAllocator) clusterClient() (*http.Client, error) {
+    a.mu.Lock()
+    defer a.mu.Unlock()
+    if a.client != nil {
+        return a.client, nil
+    }
+    pem, err := os.ReadFile(serviceAccountDir + "/ca.crt")
+    if err != nil {
+        return nil, fmt.Errorf("coordination: read cluster ca: %w", err)
+    }
+    pool := x509.NewCertPool()
+    pool.AppendCertsFromPEM(pem)
+    a.client = &http.Client{
+        Timeout:   10 * time.Second,
+        Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}},
+    }
+    return a.client, nil
+}
+
+func (a *ConfigMapAllocator) namespace() (string, error) {
+    if a.Namespace != "" {
+        return a.Namespace, nil
+    }
+    b, err := os.ReadFile(serviceAccountDir + "/namespace")
+    if err != nil {
+        return "", fmt.Errorf("coordination: read pod namespace: %w", err)
+    }
+    return strings.TrimSpace(string(b)), nil
+}
+
+func (a *ConfigMapAllocator) path(ns string, named bool) string {
+    p := "/api/v1/namespaces/" + ns + "/configmaps"
+    if named {
+        p += "/" + a.name()
+    }
+    return p
+}
+
+func (a *ConfigMapAllocator) name() string {
+    if a.Name == "" {
+        return "idgen-nodes"
+    }
+    return a.Name
+}
+
+func (a *ConfigMapAllocator) maxNode() uint16 {
+    if a.MaxNode == 0 {
+        return 0xffff
+    }
+    return a.MaxNode
+}
@@ -0,0 +1,33 @@
+package coordination
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+)
+
+func TestConfigMapAllocatorReusesClient(t *testing.T) {
+    dir := t.TempDir()
+    old := serviceAccountDir
+    serviceAccountDir = dir
+    t.Cleanup(func() { serviceAccountDir = old })
+
+    a := &ConfigMapAllocator{APIServer: "https://kubernetes.test", Token: "token"}
+    if _, _, _, err := a.config(); err == nil {
+        t.Fatal("config succeeded without a cluster ca")
+    }
+    if err := os.WriteFile(filepath.Join(dir, "ca.crt"), nil, 0o600); err != nil {
+        t.Fatal(err)
+    }
+    _, _, first, err := a.config()
+    if err != nil {
+        t.Fatal(err)
+    }
+    _, _, second, err := a.config()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if first != second {
+        t.Fatal("config built a new http.Client on each call")
+    }
+}
@@ -0,0 +1,169 @@
+package coordination
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "fmt"
+    "strconv"
+    "sync"
+    "time"
+
+    "github.com/redis/go-redis/v9"
+)
+
+// claimScript 以 SET NX PX 取得節點；key 已存在但沒有 TTL (殘留的 key，例如手動寫入) 時直接覆寫取得
+// 檢查與取得在同一個腳本內原子完成，避免兩個副本同時回收同一個殘留 key 而都認為自己取得
+var claimScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+    return 1
+end
+if redis.call("PTTL", KEYS[1]) == -1 then
+    redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+    return 1
+end
+return 0`)
+
+// renewScript 僅在 key 仍屬於自己時延長 TTL，避免續約到他人已取得的節點
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+    return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// releaseScript 僅在 key 仍屬於自己時刪除
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+    return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// RedisAllocator 以 Redis SET NX + TTL 分配節點 ID，並於背景定期續約
+// 持有者停止續約 (程序結束或失聯) 後 key 於 TTL 到期自動刪除，節點 ID 即被回收
+type RedisAllocator struct {
+    Client  redis.UniversalClient
+    Region  uint16
+    Prefix  string        // key 前綴，預設 "idgen:nodes:"
+    TTL     time.Duration // key 存活時間，預設 10 秒；續約間隔為 TTL/3
+    MaxNode uint16        // 可分配的最大節點 ID，0 代表使用 65535
+    Owner   string        // 寫入值的前綴，方便除錯；預設為 hostname:pid
+
+    // OnLost 在續約失敗或 key 已被他人取得時被呼叫
+    // 此時節點 ID 可能已被其他副本使用，應停止產生 ID 或重新 Acquire
+    OnLost func(region, node uint16)
+}
+
+// NewRedisAllocator 建立在 region 內分配節點 ID 的 RedisAllocator
+func NewRedisAllocator(client redis.UniversalClient, region uint16) *RedisAllocator {
+    return &RedisAllocator{Client: client, Region: region}
+}
+
+// Acquire 取得一個尚未被占用的節點 ID，並於背景持續續約
+// 沒有設定 TTL 的殘留 key (例如手動寫入) 視為失效並回收
+func (a *RedisAllocator) Acquire(ctx context.Context) (region, node uint16, release func(), err error) {
+    token, err := a.token()
+    if err != nil {
+        return 0, 0, nil, err
+    }
+    ttl := a.ttl()
+
+    for n := 0; n <= int(a.maxNode()); n++ {
+        key := a.key(uint16(n))
+        claimed, err := claimScript.Run(ctx, a.Client, []string{key}, token, ttl.Milliseconds()).Int()
+        if err != nil {
+            return 0, 0, nil, fmt.Errorf("coordination: claim node %d: %w", n, err)
+        }
+        if claimed != 1 { // 有 TTL 的 key 屬於存活的持有者，交由 Redis 到期刪除
+            continue
+        }
+
+        hbCtx, stop := context.WithCancel(context.Background())
+        done := make(chan struct{})
+        go a.heartbeat(hbCtx, done, key, token, uint16(n))
+
+        var once sync.Once
+        release = func() {
+            once.Do(func() {
+                stop()
+                <-done
+                rctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+                defer cancel()
+                releaseScript.Run(rctx, a.Client, []string{key}, token)
+            })
+        }
+        return a.Region, uint16(n), release, nil
+    }
+    return 0, 0, nil, fmt.Errorf("%w in region %d", ErrNoFreeNode, a.Region)
+}
+
+// heartbeat 每 TTL/3 續約一次，直到 ctx 結束或確認節點已遺失
+// 暫時性錯誤會在下次重試，只有 key 已不屬於自己或超過 TTL 未能續約才視為遺失
+func (a *RedisAllocator) heartbeat(ctx context.Context, done chan<- struct{}, key, token string, node uint16) {
+    defer close(done)
+
+    ttl := a.ttl()
+    renewed := time.Now()
+    ticker := time.NewTicker(ttl / 3)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+
+        res, err := renewScript.Run(ctx, a.Client, []string{key}, token, ttl.Milliseconds()).Int()
+        if ctx.Err() != nil {
+            return
+        }
+        switch {
+        case err == nil && res == 1:
+            renewed = time.Now()
+            continue
+        case err != nil && time.Since(renewed) < ttl:
+            continue
+        }
+        if a.OnLost != nil {
+            a.OnLost(a.Region, node)
+        }
+        return
+    }
+}
+
+func (a *RedisAllocator) key(node uint16) string {
+    prefix := a.Prefix
+    if prefix == "" {
+        prefix = "idgen:nodes:"
+    }
+    return prefix + strconv.Itoa(int(a.Region)) + ":" + strconv.Itoa(int(node))
+}
+
+func (a *RedisAllocator) ttl() time.Duration {
+    if a.TTL <= 0 {
+        return 10 * time.Second
+    }
+    return a.TTL
+}
+
+func (a *RedisAllocator) maxNode() uint16 {
+    if a.MaxNode == 0 {
+        return 65535
+    }
+    return a.MaxNode
+}
+
+// token 產生本次持有的唯一值，確保續約與釋放只作用在自己的 key
+func (a *RedisAllocator) token() (string, error) {
+    var b [8]byte
+    if _, err := rand.Read(b[:]); err != nil {
+        return "", fmt.Errorf("coordination: generate token: %w", err)
+    }
+    return a.ownerName() + "/" + hex.EncodeToString(b[:]), nil
+}
+
+func (a *RedisAllocator) ownerName() string {
+    if a.Owner != "" {
+        return a.Owner
+    }
+    return defaultOwner()
+}
@@ -0,0 +1,94 @@
+package idgen
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/base64"
+    "fmt"
+)
+
+// ------------- 分頁游標 ------------- //
+
+const (
+    cursorVersion = 1
+    cursorMACLen  = 16 // 截斷後的 HMAC‑SHA256 長度
+    cursorMinKey  = 16
+)
+
+// Cursor 為 keyset 分頁游標：上一頁最後一筆的 ID，以及呼叫端自訂的不透明資料 (例如排序方向或篩選條件)
+//
+//    SELECT ... WHERE id > $1 ORDER BY id LIMIT 50
+type Cursor struct {
+    ID      ID
+    Payload []byte
+}
+
+// CursorCodec 將 Cursor 編碼為帶簽章、URL‑safe 的字串，並於解碼時偵測竄改
+// 簽章為 HMAC‑SHA256 (截斷為 16 bytes)；Payload 僅簽章不加密，請勿放入機密資料
+type CursorCodec struct {
+    keys [][]byte
+}
+
+// NewCursorCodec 以 key 建立 CursorCodec，key 至少 16 bytes
+// oldKeys 僅用於驗證，輪替金鑰時可讓先前簽發的游標在過渡期內仍然有效
+func NewCursorCodec(key []byte, oldKeys ...[]byte) (*CursorCodec, error) {
+    c := &CursorCodec{}
+    for _, k := range append([][]byte{key}, oldKeys...) {
+        if len(k) < cursorMinKey {
+            return nil, fmt.Errorf("idgen: cursor key must be at least %d bytes", cursorMinKey)
+        }
+        c.keys = append(c.keys, append([]byte(nil), k...))
+    }
+    return c, nil
+}
+
+// Encode 回傳 cur 的游標字串 (base64url，無填充)
+func (c *CursorCodec) Encode(cur Cursor) string {
+    buf := make([]byte, 0, 1+len(cur.ID)+len(cur.Payload)+cursorMACLen)
+    buf = append(buf, cursorVersion)
+    buf = append(buf, cur.ID[:]...)
+    buf = append(buf, cur.Payload...)
+    buf = append(buf, c.sign(c.keys[0], buf)...)
+    return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// Decode 解析並驗證 Encode 產生的游標字串
+// 格式錯誤或簽章不符時回傳 ErrInvalidCursor
+func (c *CursorCodec) Decode(s string) (Cursor, error) {
+    var cur Cursor
+    raw, err := base64.RawURLEncoding.DecodeString(s)
+    if err != nil {
+        return cur, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+    }
+    if len(raw) < 1+len(cur.ID)+cursorMACLen {
+        return cur, fmt.Errorf("%w: too short", ErrInvalidCursor)
+    }
+    if raw[0] != cursorVersion {
+        return cur, fmt.Errorf("%w: unknown version %d", ErrInvalidCursor, raw[0])
+    }
+
+    body, mac := raw[:len(raw)-cursorMACLen], raw[len(raw)-cursorMACLen:]
+    if !c.verify(body, mac) {
+        return cur, fmt.Errorf("%w: signature mismatch", ErrInvalidCursor)
+    }
+    copy(cur.ID[:], body[1:])
+    if p := body[1+len(cur.ID):]; len(p) > 0 {
+        cur.Payload = p
+    }
+    return cur, nil
+}
+
+func (c *CursorCodec) sign(key, body []byte) []byte {
+    h := hmac.New(sha256.New, key)
+    h.Write(body)
+    return h.Sum(nil)[:cursorMACLen]
+}
+
+func (c *CursorCodec) verify(body, mac []byte) bool {
+    for _, k := range c.keys {
+        if hmac.Equal(c.sign(k, body), mac) {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,137 @@
+// Package dedup 提供以 Bloom filter 實作的滑動視窗重複偵測，用於稽核 ID 串流中的意外碰撞
+// (例如正式環境中兩個節點誤設為相同的節點 ID)
+//
+//    d, _ := dedup.New(10_000_000, 1e-6)
+//    for id := range stream {
+//        if d.Seen(id) {
+//            log.Printf("possible duplicate id %s", id)
+//        }
+//    }
+//
+// Bloom filter 只會誤報不會漏報：Seen 回傳 false 的 ID 必定未出現在視窗內，
+// 回傳 true 的 ID 則需另行比對確認 (誤報率約為 New 指定的 falsePositive)
+package dedup
+
+import (
+    "fmt"
+    "hash/maphash"
+    "math"
+    "sync"
+    "sync/atomic"
+
+    "github.com/pascal910107/idgen"
+)
+
+// Detector 記住最近 window 到 2*window 個 ID 的滑動視窗重複偵測器 (thread‑safe)
+//
+// 內部維持兩代 Bloom filter：目前一代加入滿 window 個 ID 後，捨棄上一代並換上新的一代，
+// 查詢時同時檢查兩代；記憶體用量約為 2 * window * 1.44 * log2(1/falsePositive) bits
+type Detector struct {
+    mu      sync.Mutex
+    window  uint64
+    cur     *bloom
+    prev    *bloom
+    seed1   maphash.Seed
+    seed2   maphash.Seed
+    dups    atomic.Uint64
+    checked atomic.Uint64
+}
+
+// New 建立視窗大小為 window、每代誤報率約為 falsePositive 的 Detector
+func New(window int, falsePositive float64) (*Detector, error) {
+    if window <= 0 {
+        return nil, fmt.Errorf("dedup: window must be positive, got %d", window)
+    }
+    if falsePositive <= 0 || falsePositive >= 1 {
+        return nil, fmt.Errorf("dedup: false positive rate %v not in (0, 1)", falsePositive)
+    }
+    n := float64(window)
+    m := uint64(math.Ceil(-n * math.Log(falsePositive) / (math.Ln2 * math.Ln2)))
+    k := uint32(math.Max(1, math.Round(float64(m)/n*math.Ln2)))
+
+    return &Detector{
+        window: uint64(window),
+        cur:    newBloom(m, k),
+        prev:   newBloom(m, k),
+        seed1:  maphash.MakeSeed(),
+        seed2:  maphash.MakeSeed(),
+    }, nil
+}
+
+// Seen 檢查 id 是否 (可能) 已出現在視窗內，並將其加入視窗
+// 回傳 true 時累加 Duplicates 計數
+func (d *Detector) Seen(id idgen.ID) bool {
+    h1 := maphash.Bytes(d.seed1, id[:])
+    h2 := maphash.Bytes(d.seed2, id[:]) | 1 // 奇數步長，避免與位元數有公因數時重複探測相同位置
+
+    d.mu.Lock()
+    seen := d.cur.test(h1, h2) || d.prev.test(h1, h2)
+    if !seen {
+        if d.cur.n >= d.window {
+            d.prev, d.cur = d.cur, d.prev
+            d.cur.reset()
+        }
+        d.cur.add(h1, h2)
+    }
+    d.mu.Unlock()
+
+    d.checked.Add(1)
+    if seen {
+        d.dups.Add(1)
+    }
+    return seen
+}
+
+// Checked 回傳累計檢查的 ID 數
+func (d *Detector) Checked() uint64 { return d.checked.Load() }
+
+// Duplicates 回傳累計 Seen 回傳 true 的次數 (含誤報)
+func (d *Detector) Duplicates() uint64 { return d.dups.Load() }
+
+// Reset 清空視窗與計數
+func (d *Detector) Reset() {
+    d.mu.Lock()
+    d.cur.reset()
+    d.prev.reset()
+    d.mu.Unlock()
+    d.checked.Store(0)
+    d.dups.Store(0)
+}
+
+// ------------- Bloom filter ------------- //
+
+type bloom struct {
+    bits []uint64
+    m    uint64 // 位元數
+    k    uint32 // 雜湊函數個數
+    n    uint64 // 已加入的元素數
+}
+
+func newBloom(m uint64, k uint32) *bloom {
+    words := (m + 63) / 64
+    return &bloom{bits: make([]uint64, words), m: words * 64, k: k}
+}
+
+// test 與 add 以 double hashing (h1 + i*h2) 模擬 k 個雜湊函數
+func (b *bloom) test(h1, h2 uint64) bool {
+    for i := uint32(0); i < b.k; i++ {
+        pos := (h1 + uint64(i)*h2) % b.m
+        if b.bits[pos/64]&(1<<(pos%64)) == 0 {
+            return false
+        }
+    }
+    return true
+}
+
+func (b *bloom) add(h1, h2 uint64) {
+    for i := uint32(0); i < b.k; i++ {
+        pos := (h1 + uint64(i)*h2) % b.m
+        b.bits[pos/64] |= 1 << (pos % 64)
+    }
+    b.n++
+}
+
+func (b *bloom) reset() {
+    clear(b.bits)
+    b.n = 0
+}
@@ -0,0 +1,70 @@
+package dedup_test
+
+import (
+    "testing"
+
+    "github.com/pascal910107/idgen"
+    "github.com/pascal910107/idgen/dedup"
+)
+
+func TestDetectorSeen(t *testing.T) {
+    d, err := dedup.New(1000, 1e-6)
+    if err != nil {
+        t.Fatal(err)
+    }
+    ids := make([]idgen.ID, 1000)
+    for i := range ids {
+        ids[i] = idgen.FromParts(0, uint64(i+1), 1, 2, 0)
+        if d.Seen(ids[i]) {
+            t.Fatalf("fresh id %d reported as seen", i)
+        }
+    }
+    for i, id := range ids {
+        if !d.Seen(id) {
+            t.Fatalf("repeated id %d not reported", i)
+        }
+    }
+    if d.Checked() != 2000 || d.Duplicates() != 1000 {
+        t.Fatalf("Checked = %d, Duplicates = %d, want 2000, 1000", d.Checked(), d.Duplicates())
+    }
+
+    d.Reset()
+    if d.Seen(ids[0]) || d.Checked() != 1 || d.Duplicates() != 0 {
+        t.Fatal("Reset did not clear the window and counters")
+    }
+}
+
+func TestDetectorWindowSlides(t *testing.T) {
+    d, err := dedup.New(100, 1e-6)
+    if err != nil {
+        t.Fatal(err)
+    }
+    first := idgen.FromParts(0, 1, 1, 2, 0)
+    d.Seen(first)
+    // 再加入兩代以上的 ID 後，最早的 ID 已離開視窗
+    for i := range 300 {
+        d.Seen(idgen.FromParts(0, uint64(i+2), 1, 2, 0))
+    }
+    if d.Seen(first) {
+        t.Fatal("id still reported after leaving the window")
+    }
+}
+
+func TestNewRejects(t *testing.T) {
+    tests := []struct {
+        name   string
+        window int
+        fp     float64
+    }{
+        {"zero window", 0, 0.01},
+        {"zero rate", 10, 0},
+        {"rate one", 10, 1},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if _, err := dedup.New(tt.window, tt.fp); err == nil {
+                t.Fatalf("New(%d, %v) succeeded", tt.window, tt.fp)
+            }
+        })
+    }
+}
@@ -0,0 +1,54 @@
+package idgen
+
+import "sync/atomic"
+
+// ------------- 套件層級預設產生器 ------------- //
+
+// defaultGen 保存套件層級的預設 Generator，尚未初始化時為 nil
+var defaultGen atomic.Pointer[Generator]
+
+// Init 以 regionID 與 nodeID 設定預設產生器，應於程式啟動時、呼叫 Next 之前執行一次
+// 可另外傳入選項，例如 Init(1, 42, WithClock(c))
+// 預設產生器已存在 (再次呼叫 Init，或先前已呼叫 Default、Next) 時回傳 ErrAlreadyInitialized：
+// 換上新的 Generator 會從頭計算序列號，可能重複發出同一毫秒內已發出的 ID
+func Init(regionID, nodeID uint16, opts ...Option) error {
+    if defaultGen.Load() != nil {
+        return ErrAlreadyInitialized
+    }
+    g, err := New(append([]Option{WithRegion(regionID), WithNode(nodeID)}, opts...)...)
+    if err != nil {
+        return err
+    }
+    if !defaultGen.CompareAndSwap(nil, g) {
+        g.Release()
+        return ErrAlreadyInitialized
+    }
+    return nil
+}
+
+// Default 回傳預設產生器；未呼叫 Init 時會以 region=0、node=0 建立
+// 多個程序共用預設值會產生重複 ID，正式環境請先呼叫 Init
+func Default() *Generator {
+    if g := defaultGen.Load(); g != nil {
+        return g
+    }
+    g, _ := New() // 預設值必定通過驗證
+    if defaultGen.CompareAndSwap(nil, g) {
+        return g
+    }
+    return defaultGen.Load()
+}
+
+// Next 以預設產生器產生下一個 ID
+func Next() (ID, error) {
+    return Default().Next()
+}
+
+// MustNext 同 Next，發生錯誤時 panic
+func MustNext() ID {
+    id, err := Next()
+    if err != nil {
+        panic(err)
+    }
+    return id
+}
@@ -0,0 +1,33 @@
+package idgen
+
+import (
+    "errors"
+    "testing"
+)
+
+func TestInitOnce(t *testing.T) {
+    tests := []struct {
+        name  string
+        first func() error
+    }{
+        {"second Init", func() error { return Init(1, 1) }},
+        {"Init after lazy Default", func() error { _, err := Next(); return err }},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            defaultGen.Store(nil)
+            t.Cleanup(func() { defaultGen.Store(nil) })
+
+            if err := tt.first(); err != nil {
+                t.Fatal(err)
+            }
+            before := Default()
+            if err := Init(2, 2); !errors.Is(err, ErrAlreadyInitialized) {
+                t.Fatalf("err = %v, want ErrAlreadyInitialized", err)
+            }
+            if Default() != before {
+                t.Fatal("rejected Init replaced the default generator")
+            }
+        })
+    }
+}
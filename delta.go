@@ -0,0 +1,193 @@
+package idgen
+
+import (
+    "bufio"
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "io"
+    "math"
+)
+
+// ------------- 差分壓縮串流 ------------- //
+
+// deltaMagic 為差分串流的檔頭 (含格式版本)
+var deltaMagic = [4]byte{'I', 'D', 'D', 1}
+
+// 每筆記錄開頭的旗標，標示哪些欄位與前一筆不同
+const (
+    deltaEpoch  = 1 << iota // epoch 改變，時間戳改寫絕對值
+    deltaRegion             // region 改變
+    deltaNode               // node 改變
+)
+
+// DeltaWriter 將遞增排序的 ID 串流以差分 + varint 壓縮寫入 io.Writer
+//
+// 每筆記錄為 1 byte 旗標、時間戳差值，以及變動時才寫入的 region / node；
+// 同一毫秒、同一節點的連續 ID 只寫序列號差值。典型的匯出清單每個 ID 約 3 bytes，原始格式為 16 bytes
+//
+// 欄位依 DefaultLayout 切分；其他配置的 ID 仍可無損還原，只是壓縮率較差
+type DeltaWriter struct {
+    w       *bufio.Writer
+    prev    ID
+    started bool
+    buf     []byte
+}
+
+// NewDeltaWriter 建立寫入 w 的 DeltaWriter，寫完後須呼叫 Flush
+func NewDeltaWriter(w io.Writer) *DeltaWriter {
+    return &DeltaWriter{w: bufio.NewWriter(w), buf: make([]byte, 0, 1+4*binary.MaxVarintLen64)}
+}
+
+// Write 寫入下一個 ID，id 小於前一個 ID 時回傳錯誤 (允許重複)
+func (dw *DeltaWriter) Write(id ID) error {
+    if err := dw.header(); err != nil {
+        return err
+    }
+    if id.Compare(dw.prev) < 0 {
+        return fmt.Errorf("idgen: delta writer requires ascending ids, got %s after %s", id, dw.prev)
+    }
+
+    prev := dw.prev
+    var flags byte
+    if id.Epoch() != prev.Epoch() {
+        flags |= deltaEpoch
+    }
+    if id.Region() != prev.Region() {
+        flags |= deltaRegion
+    }
+    if id.Node() != prev.Node() {
+        flags |= deltaNode
+    }
+
+    b := append(dw.buf[:0], flags)
+    if flags&deltaEpoch != 0 {
+        b = binary.AppendUvarint(b, uint64(id.Epoch()))
+        b = binary.AppendUvarint(b, id.TimestampMillis())
+    } else {
+        b = binary.AppendUvarint(b, id.TimestampMillis()-prev.TimestampMillis())
+    }
+    if flags&deltaRegion != 0 {
+        b = binary.AppendUvarint(b, uint64(id.Region()))
+    }
+    if flags&deltaNode != 0 {
+        b = binary.AppendUvarint(b, uint64(id.Node()))
+    }
+    if flags == 0 && id.TimestampMillis() == prev.TimestampMillis() {
+        b = binary.AppendUvarint(b, uint64(id.Sequence()-prev.Sequence()))
+    } else {
+        b = binary.AppendUvarint(b, uint64(id.Sequence()))
+    }
+
+    dw.buf = b
+    dw.prev = id
+    _, err := dw.w.Write(b)
+    return err
+}
+
+// Flush 將緩衝中的資料寫入底層 io.Writer；未寫入任何 ID 時仍會輸出檔頭
+func (dw *DeltaWriter) Flush() error {
+    if err := dw.header(); err != nil {
+        return err
+    }
+    return dw.w.Flush()
+}
+
+func (dw *DeltaWriter) header() error {
+    if dw.started {
+        return nil
+    }
+    dw.started = true
+    _, err := dw.w.Write(deltaMagic[:])
+    return err
+}
+
+// DeltaReader 讀取 DeltaWriter 產生的串流
+type DeltaReader struct {
+    r       *bufio.Reader
+    prev    ID
+    started bool
+}
+
+// NewDeltaReader 建立自 r 讀取的 DeltaReader
+func NewDeltaReader(r io.Reader) *DeltaReader {
+    return &DeltaReader{r: bufio.NewReader(r)}
+}
+
+// Read 回傳下一個 ID，串流結束時回傳 io.EOF
+// 串流在記錄中途截斷時回傳 io.ErrUnexpectedEOF，內容不合法時回傳 ErrInvalidEncoding
+func (dr *DeltaReader) Read() (ID, error) {
+    if !dr.started {
+        var magic [4]byte
+        if _, err := io.ReadFull(dr.r, magic[:]); err != nil {
+            return ID{}, err
+        }
+        if magic != deltaMagic {
+            return ID{}, fmt.Errorf("%w: not a delta id stream", ErrInvalidEncoding)
+        }
+        dr.started = true
+    }
+
+    flags, err := dr.r.ReadByte()
+    if err != nil {
+        return ID{}, err
+    }
+    if flags&^(deltaEpoch|deltaRegion|deltaNode) != 0 {
+        return ID{}, fmt.Errorf("%w: unknown delta flags %#x", ErrInvalidEncoding, flags)
+    }
+
+    prev := dr.prev
+    epoch, ts, region, node := uint64(prev.Epoch()), prev.TimestampMillis(), uint64(prev.Region()), uint64(prev.Node())
+    var seq uint64
+    if flags&deltaEpoch != 0 {
+        if epoch, err = dr.uvarint(math.MaxUint16); err != nil {
+            return ID{}, err
+        }
+        if ts, err = dr.uvarint(math.MaxUint64); err != nil {
+            return ID{}, err
+        }
+    } else {
+        d, err := dr.uvarint(math.MaxUint64 - ts)
+        if err != nil {
+            return ID{}, err
+        }
+        ts += d
+    }
+    if flags&deltaRegion != 0 {
+        if region, err = dr.uvarint(math.MaxUint16); err != nil {
+            return ID{}, err
+        }
+    }
+    if flags&deltaNode != 0 {
+        if node, err = dr.uvarint(math.MaxUint16); err != nil {
+            return ID{}, err
+        }
+    }
+    if flags == 0 && ts == prev.TimestampMillis() {
+        d, err := dr.uvarint(uint64(math.MaxUint16 - prev.Sequence()))
+        if err != nil {
+            return ID{}, err
+        }
+        seq = uint64(prev.Sequence()) + d
+    } else if seq, err = dr.uvarint(math.MaxUint16); err != nil {
+        return ID{}, err
+    }
+
+    id := makeID(uint16(epoch), ts, uint16(region), uint16(node), uint16(seq))
+    dr.prev = id
+    return id, nil
+}
+
+// uvarint 讀取不超過 max 的 uvarint；記錄開頭之後的 EOF 視為截斷
+func (dr *DeltaReader) uvarint(max uint64) (uint64, error) {
+    v, err := binary.ReadUvarint(dr.r)
+    switch {
+    case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+        return 0, io.ErrUnexpectedEOF
+    case err != nil:
+        return 0, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+    case v > max:
+        return 0, fmt.Errorf("%w: delta field %d out of range", ErrInvalidEncoding, v)
+    }
+    return v, nil
+}
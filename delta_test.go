@@ -0,0 +1,103 @@
+package idgen_test
+
+import (
+    "bytes"
+    "errors"
+    "io"
+    "testing"
+
+    "github.com/pascal910107/idgen"
+)
+
+func TestDeltaRoundTrip(t *testing.T) {
+    g, err := idgen.New(idgen.WithRegionNode(1, 2))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer g.Release()
+    generated, err := g.NextN(10_000)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        name string
+        ids  []idgen.ID
+    }{
+        {"empty", nil},
+        {"generated", generated},
+        {"duplicates", []idgen.ID{generated[0], generated[0], generated[1]}},
+        {"mixed fields", []idgen.ID{
+            idgen.FromParts(0, 100, 1, 1, 65535),
+            idgen.FromParts(0, 100, 1, 2, 0),
+            idgen.FromParts(0, 100, 2, 0, 7),
+            idgen.FromParts(0, 1<<40, 2, 0, 0),
+            idgen.FromParts(1, 5, 0, 0, 0),
+            idgen.FromParts(65535, 1<<62, 65535, 65535, 65535),
+        }},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var buf bytes.Buffer
+            w := idgen.NewDeltaWriter(&buf)
+            for _, id := range tt.ids {
+                if err := w.Write(id); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            if err := w.Flush(); err != nil {
+                t.Fatal(err)
+            }
+            size := buf.Len()
+
+            r := idgen.NewDeltaReader(&buf)
+            for i, want := range tt.ids {
+                got, err := r.Read()
+                if err != nil || got != want {
+                    t.Fatalf("id %d = %s, %v, want %s", i, got, err, want)
+                }
+            }
+            if _, err := r.Read(); err != io.EOF {
+                t.Fatalf("Read at end error = %v, want io.EOF", err)
+            }
+            if tt.name == "generated" && size > 4*len(tt.ids) {
+                t.Fatalf("%d ids encoded to %d bytes", len(tt.ids), size)
+            }
+        })
+    }
+}
+
+func TestDeltaWriterRejectsDescending(t *testing.T) {
+    w := idgen.NewDeltaWriter(io.Discard)
+    if err := w.Write(idgen.FromParts(0, 2, 0, 0, 0)); err != nil {
+        t.Fatal(err)
+    }
+    if err := w.Write(idgen.FromParts(0, 1, 0, 0, 0)); err == nil {
+        t.Fatal("Write accepted a descending id")
+    }
+}
+
+func TestDeltaReaderErrors(t *testing.T) {
+    var buf bytes.Buffer
+    w := idgen.NewDeltaWriter(&buf)
+    w.Write(idgen.FromParts(1, 1<<40, 3, 4, 5))
+    w.Flush()
+    stream := buf.Bytes()
+
+    tests := []struct {
+        name string
+        in   []byte
+        want error
+    }{
+        {"bad magic", []byte("NOPE"), idgen.ErrInvalidEncoding},
+        {"truncated record", stream[:len(stream)-1], io.ErrUnexpectedEOF},
+        {"unknown flags", append(stream[:4:4], 0x80), idgen.ErrInvalidEncoding},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if _, err := idgen.NewDeltaReader(bytes.NewReader(tt.in)).Read(); !errors.Is(err, tt.want) {
+                t.Fatalf("Read error = %v, want %v", err, tt.want)
+            }
+        })
+    }
+}
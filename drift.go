@@ -0,0 +1,72 @@
+package idgen
+
+import (
+    "sync"
+    "time"
+)
+
+// ------------- 時鐘偏移監控 ------------- //
+
+// DriftEvent 描述一次偵測到的時鐘偏移
+// Drift 為牆上時間相對單調時鐘的變化量：正值代表時間被往前調，負值代表回撥
+type DriftEvent struct {
+    Drift    time.Duration
+    Expected time.Time // 依單調時鐘推算應有的時間
+    Wall     time.Time // 實際讀到的系統時間
+}
+
+// WatchDrift 於背景每隔 interval 比對系統時間與單調時鐘，
+// 兩者的變化差距超過 threshold 時以 DriftEvent 呼叫 fn
+// 可在 ID 順序受影響前得知 NTP 大幅校正或手動改時間；回傳的 stop 會停止監控並等待背景結束
+func WatchDrift(interval, threshold time.Duration, fn func(DriftEvent)) (stop func()) {
+    done := make(chan struct{})
+    exited := make(chan struct{})
+    go func() {
+        defer close(exited)
+
+        ticker := time.NewTicker(interval)
+        defer ticker.Stop()
+        prev := time.Now()
+        for {
+            select {
+            case <-done:
+                return
+            case <-ticker.C:
+            }
+
+            now := time.Now()
+            expected := prev.Round(0).Add(now.Sub(prev)) // now.Sub(prev) 只依單調時鐘
+            wall := now.Round(0)
+            if drift := wall.Sub(expected); drift > threshold || drift < -threshold {
+                fn(DriftEvent{Drift: drift, Expected: expected, Wall: wall})
+            }
+            prev = now
+        }
+    }()
+
+    var once sync.Once
+    return func() {
+        once.Do(func() {
+            close(done)
+            <-exited
+        })
+    }
+}
+
+// WithDriftMonitor 於 Generator 存續期間以 WatchDrift 監控系統時鐘，
+// 每次偏移超過 threshold 時回報 MetricClockDrift 並呼叫 fn (可為 nil)
+// 監控於 New 成功建立 Generator 後才開始 (此時指標設定已確定)，於 Generator.Release 時停止
+func WithDriftMonitor(interval, threshold time.Duration, fn func(DriftEvent)) Option {
+    return func(g *Generator) error {
+        g.starts = append(g.starts, func() {
+            stop := WatchDrift(interval, threshold, func(ev DriftEvent) {
+                g.metrics.ObserveDuration(MetricClockDrift, ev.Drift.Abs())
+                if fn != nil {
+                    fn(ev)
+                }
+            })
+            g.releases = append(g.releases, stop)
+        })
+        return nil
+    }
+}
@@ -0,0 +1,38 @@
+package idgen_test
+
+import (
+    "testing"
+    "time"
+
+    "github.com/pascal910107/idgen"
+)
+
+func TestDriftMonitorReportsMetrics(t *testing.T) {
+    observed := make(chan struct{}, 1)
+    m := idgen.MetricsFuncs{Duration: func(name string, d time.Duration) {
+        if name == idgen.MetricClockDrift {
+            select {
+            case observed <- struct{}{}:
+            default:
+            }
+        }
+    }}
+    // 負的 threshold 讓每次比對都視為偏移，藉此觸發回報
+    g, err := idgen.New(idgen.WithDriftMonitor(time.Millisecond, -1, nil), idgen.WithMetrics(m))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer g.Release()
+    if _, err := g.Next(); err != nil {
+        t.Fatal(err)
+    }
+
+    select {
+    case <-observed:
+    case <-time.After(time.Second):
+        t.Fatal("drift was not reported to metrics")
+    }
+    if s := g.Stats(); s.IDsIssued != 1 {
+        t.Fatalf("IDsIssued = %d, want 1", s.IDsIssued)
+    }
+}
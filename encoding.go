@@ -0,0 +1,162 @@
+package idgen
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "sync/atomic"
+)
+
+// ------------- 編碼介面實作 ------------- //
+
+// Format 表示 ID 對外的儲存或傳輸格式
+// 除 FormatBase64URL 外，各格式的字串依字典序排序即為 ID 的時間順序
+type Format int
+
+const (
+    FormatBinary          Format = iota // 原始 16 bytes
+    FormatHex                           // 32 字元十六進位
+    FormatUUID                          // 8-4-4-4-12 UUID 字串
+    FormatBase64URL                     // 22 字元 Base64 URL‑safe (字典序與 ID 順序不一致)
+    FormatBase32Crockford               // 26 字元 Crockford Base32
+    FormatBase64Sortable                // 22 字元有序字元集 Base64，Parse 無法自動辨識
+    FormatBase32Check                   // 27 字元 Crockford Base32 加檢查碼，供人工輸入
+)
+
+// formatNames 為各 Format 的文字名稱，用於設定檔、命令列與 HTTP 參數
+var formatNames = map[Format]string{
+    FormatBinary:          "binary",
+    FormatHex:             "hex",
+    FormatUUID:            "uuid",
+    FormatBase64URL:       "base64url",
+    FormatBase32Crockford: "base32",
+    FormatBase64Sortable:  "base64sort",
+    FormatBase32Check:     "base32check",
+}
+
+// String 回傳格式名稱，例如 "hex"、"uuid"
+func (f Format) String() string {
+    if name, ok := formatNames[f]; ok {
+        return name
+    }
+    return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// ParseFormat 將格式名稱 (binary、hex、uuid、base64url、base32、base64sort、base32check) 轉為 Format
+func ParseFormat(name string) (Format, error) {
+    for f, n := range formatNames {
+        if n == name {
+            return f, nil
+        }
+    }
+    return 0, fmt.Errorf("idgen: unknown format %q", name)
+}
+
+// Encode 依 f 輸出字串表示；FormatBinary 不屬於字串格式，會回傳錯誤
+func (id ID) Encode(f Format) (string, error) {
+    switch f {
+    case FormatHex:
+        return id.Hex(), nil
+    case FormatUUID:
+        return id.UUIDString(), nil
+    case FormatBase64URL:
+        return id.Base64URL(), nil
+    case FormatBase32Crockford:
+        return id.Base32Crockford(), nil
+    case FormatBase64Sortable:
+        return id.Base64Sortable(), nil
+    case FormatBase32Check:
+        return id.Base32Check(), nil
+    default:
+        return "", fmt.Errorf("idgen: unsupported string format %v", f)
+    }
+}
+
+// MarshalText 實作 encoding.TextMarshaler，預設輸出 hex 字串
+func (id ID) MarshalText() ([]byte, error) {
+    return id.AppendHex(make([]byte, 0, 2*len(id))), nil
+}
+
+// AppendText 實作 encoding.TextAppender，將 hex 字串附加到 dst 後回傳
+func (id ID) AppendText(dst []byte) ([]byte, error) {
+    return id.AppendHex(dst), nil
+}
+
+// UnmarshalText 實作 encoding.TextUnmarshaler，接受 Parse 支援的任一格式
+func (id *ID) UnmarshalText(text []byte) error {
+    parsed, err := ParseBytes(text)
+    if err != nil {
+        return err
+    }
+    *id = parsed
+    return nil
+}
+
+// MarshalBinary 實作 encoding.BinaryMarshaler，回傳原始 16 bytes
+func (id ID) MarshalBinary() ([]byte, error) {
+    b := make([]byte, len(id))
+    copy(b, id[:])
+    return b, nil
+}
+
+// UnmarshalBinary 實作 encoding.BinaryUnmarshaler，輸入必須剛好 16 bytes
+func (id *ID) UnmarshalBinary(data []byte) error {
+    if len(data) != len(id) {
+        return fmt.Errorf("%w: binary length %d", ErrInvalidLength, len(data))
+    }
+    copy(id[:], data)
+    return nil
+}
+
+// jsonFormat 保存 MarshalJSON 使用的格式，於 init 設為預設的 FormatHex
+var jsonFormat atomic.Int32
+
+func init() { jsonFormat.Store(int32(FormatHex)) }
+
+// SetJSONFormat 設定 ID.MarshalJSON 輸出的字串格式 (並發安全)
+// 可選 FormatHex (預設)、FormatBase64URL、FormatUUID、FormatBase32Crockford、FormatBase64Sortable、FormatBase32Check
+func SetJSONFormat(f Format) {
+    jsonFormat.Store(int32(f))
+}
+
+// JSONFormat 回傳目前 ID.MarshalJSON 使用的格式
+func JSONFormat() Format {
+    return Format(jsonFormat.Load())
+}
+
+// MarshalJSON 實作 json.Marshaler，依 JSONFormat 輸出 JSON 字串
+func (id ID) MarshalJSON() ([]byte, error) {
+    s, err := id.Encode(JSONFormat())
+    if err != nil {
+        return nil, err
+    }
+    return json.Marshal(s)
+}
+
+// UnmarshalJSON 實作 json.Unmarshaler
+// 接受 Parse 支援的任一字串格式，以及舊版預設輸出的 16 個數字陣列；null 不做任何變更
+func (id *ID) UnmarshalJSON(data []byte) error {
+    data = bytes.TrimSpace(data)
+    switch {
+    case bytes.Equal(data, []byte("null")):
+        return nil
+    case len(data) > 0 && data[0] == '[':
+        var raw [16]byte
+        if err := json.Unmarshal(data, &raw); err != nil {
+            return fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+        }
+        *id = raw
+        return nil
+    }
+
+    var s string
+    if err := json.Unmarshal(data, &s); err != nil {
+        return fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+    }
+    parsed, err := parseAs(s, JSONFormat())
+    if err != nil {
+        return err
+    }
+    *id = parsed
+    return nil
+}
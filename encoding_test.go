@@ -0,0 +1,85 @@
+package idgen_test
+
+import (
+    "encoding/json"
+    "errors"
+    "testing"
+
+    "github.com/pascal910107/idgen"
+)
+
+func TestJSONRoundTrip(t *testing.T) {
+    defer idgen.SetJSONFormat(idgen.JSONFormat())
+    id := idgen.FromParts(1, 123456789, 2, 3, 4)
+
+    formats := []idgen.Format{
+        idgen.FormatHex,
+        idgen.FormatUUID,
+        idgen.FormatBase64URL,
+        idgen.FormatBase32Crockford,
+        idgen.FormatBase64Sortable,
+        idgen.FormatBase32Check,
+    }
+    for _, f := range formats {
+        t.Run(f.String(), func(t *testing.T) {
+            idgen.SetJSONFormat(f)
+            type row struct {
+                ID   idgen.ID  `json:"id"`
+                Ptr  *idgen.ID `json:"ptr"`
+                Skip idgen.ID  `json:"skip,omitzero"`
+            }
+            b, err := json.Marshal(row{ID: id, Ptr: &id})
+            if err != nil {
+                t.Fatal(err)
+            }
+            want, _ := id.Encode(f)
+            if exp := `{"id":"` + want + `","ptr":"` + want + `"}`; string(b) != exp {
+                t.Fatalf("Marshal = %s, want %s", b, exp)
+            }
+            var got row
+            if err := json.Unmarshal(b, &got); err != nil {
+                t.Fatal(err)
+            }
+            if got.ID != id || got.Ptr == nil || *got.Ptr != id {
+                t.Fatalf("Unmarshal(%s) = %+v", b, got)
+            }
+        })
+    }
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+    id := idgen.FromParts(1, 123456789, 2, 3, 4)
+    legacy, err := json.Marshal([16]byte(id))
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        name string
+        in   string
+        want idgen.ID
+        err  error
+    }{
+        {"hex", `"` + id.Hex() + `"`, id, nil},
+        {"uuid", `"` + id.UUIDString() + `"`, id, nil},
+        {"legacy array", string(legacy), id, nil},
+        {"null keeps value", `null`, id, nil},
+        {"number", `42`, idgen.ID{}, idgen.ErrInvalidEncoding},
+        {"bad length", `"abc"`, idgen.ID{}, idgen.ErrInvalidLength},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := id
+            err := json.Unmarshal([]byte(tt.in), &got)
+            if tt.err != nil {
+                if !errors.Is(err, tt.err) {
+                    t.Fatalf("Unmarshal(%s) error = %v, want %v", tt.in, err, tt.err)
+                }
+                return
+            }
+            if err != nil || got != tt.want {
+                t.Fatalf("Unmarshal(%s) = %s, %v, want %s", tt.in, got, err, tt.want)
+            }
+        })
+    }
+}
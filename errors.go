@@ -0,0 +1,47 @@
+package idgen
+
+import "errors"
+
+// ------------- 錯誤定義 ------------- //
+
+// 以下為可用 errors.Is 判斷的哨兵錯誤；實際回傳的錯誤可能以 %w 包裝並附帶細節
+var (
+    // ErrRegionOutOfRange 表示 region ID 超出可用範圍
+    ErrRegionOutOfRange = errors.New("idgen: region id out of range")
+    // ErrNodeOutOfRange 表示 node ID 超出可用範圍
+    ErrNodeOutOfRange = errors.New("idgen: node id out of range")
+    // ErrInvalidLength 表示輸入長度不符合任何支援的 ID 表示法
+    ErrInvalidLength = errors.New("idgen: invalid id length")
+    // ErrInvalidEncoding 表示輸入長度正確但內容無法解碼
+    ErrInvalidEncoding = errors.New("idgen: invalid id encoding")
+    // ErrChecksumMismatch 表示帶檢查碼的字串檢查碼不符，通常是人工輸入時打錯字
+    ErrChecksumMismatch = errors.New("idgen: id checksum mismatch")
+    // ErrClockRollback 表示偵測到時鐘回撥且 RollbackPolicy 要求直接回報錯誤
+    ErrClockRollback = errors.New("idgen: clock moved backwards")
+    // ErrClockBeforeEpoch 表示目前時間早於時間戳起算點 (CustomEpoch)，無法產生有效的時間戳
+    ErrClockBeforeEpoch = errors.New("idgen: clock is before custom epoch")
+    // ErrSequenceExhausted 表示同一毫秒內的序列號已用盡
+    ErrSequenceExhausted = errors.New("idgen: sequence exhausted")
+    // ErrImplausibleID 表示 ID 可以解碼但欄位不合理，例如時間戳遠在未來
+    ErrImplausibleID = errors.New("idgen: implausible id")
+    // ErrUnknownOrigin 表示 ID 宣稱的區域或節點不在部署允許的範圍內
+    ErrUnknownOrigin = errors.New("idgen: id from unknown region or node")
+    // ErrInvalidLayout 表示 Layout 的欄位位元數不合法
+    ErrInvalidLayout = errors.New("idgen: invalid bit layout")
+    // ErrInvalidPrefix 表示型別前綴不合法或與預期不符
+    ErrInvalidPrefix = errors.New("idgen: invalid id prefix")
+    // ErrNoHardwareAddress 表示找不到可用來推導節點 ID 的網路介面
+    ErrNoHardwareAddress = errors.New("idgen: no usable hardware address")
+    // ErrInvalidCursor 表示游標字串格式錯誤或遭到竄改
+    ErrInvalidCursor = errors.New("idgen: invalid cursor")
+    // ErrInvalidSignature 表示帶簽章的 ID 格式錯誤或簽章不符
+    ErrInvalidSignature = errors.New("idgen: invalid id signature")
+    // ErrGeneratorNotFound 表示 Registry 中沒有指定名稱的 Generator
+    ErrGeneratorNotFound = errors.New("idgen: generator not found")
+    // ErrAlreadyInitialized 表示預設產生器已由 Init 或 Default 建立，不可再次 Init
+    ErrAlreadyInitialized = errors.New("idgen: default generator already initialized")
+    // ErrInvalidArgument 表示呼叫參數不合法，例如 NextN 的數量為負數
+    ErrInvalidArgument = errors.New("idgen: invalid argument")
+    // ErrGeneratorClosed 表示 Generator 已被 Close 或 Shutdown，不再產生 ID
+    ErrGeneratorClosed = errors.New("idgen: generator closed")
+)
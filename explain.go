@@ -0,0 +1,91 @@
+package idgen
+
+import (
+    "encoding/json"
+    "fmt"
+    "strings"
+    "time"
+)
+
+// ------------- 人類可讀的解析 ------------- //
+
+// Explanation 為 ID 各欄位的解析結果，供支援工具與命令列的 decode 指令輸出
+// Age 為解析當下距離產生時間的時間差，ID 來自未來 (時鐘較快的節點) 時為負值
+type Explanation struct {
+    ID              string        `json:"id"`
+    Epoch           uint16        `json:"epoch"`
+    TimestampMillis uint64        `json:"timestamp_ms"`
+    Time            time.Time     `json:"time"`
+    Age             time.Duration `json:"-"`
+    Region          uint32        `json:"region"`
+    Node            uint32        `json:"node"`
+    Sequence        uint32        `json:"sequence"`
+}
+
+// Explain 以預設位元配置與 CustomEpoch 解析 id
+// 自訂位元配置或起算點的 Generator 產生的 ID 請改用 Generator.Explain
+func (id ID) Explain() Explanation {
+    return explain(id, DefaultLayout.Decode(id), id.Time())
+}
+
+// Explain 依此 Generator 的位元配置、起算點與精度解析 id
+func (g *Generator) Explain(id ID) Explanation {
+    return explain(id, g.layout.Decode(id), g.Time(id))
+}
+
+func explain(id ID, f LayoutFields, t time.Time) Explanation {
+    return Explanation{
+        ID:              id.Hex(),
+        Epoch:           f.Epoch,
+        TimestampMillis: f.TimestampMillis,
+        Time:            t,
+        Age:             time.Since(t),
+        Region:          f.Region,
+        Node:            f.Node,
+        Sequence:        f.Sequence,
+    }
+}
+
+// String 回傳多行的欄位說明，例如
+//
+//    id         00000000000d25e6baa0000100020000
+//    epoch      0
+//    timestamp  56472374336 (2026-10-16T08:12:54.336Z, 3m12s ago)
+//    region     1
+//    node       2
+//    sequence   0
+func (e Explanation) String() string {
+    var b strings.Builder
+    fmt.Fprintf(&b, "id         %s\n", e.ID)
+    fmt.Fprintf(&b, "epoch      %d\n", e.Epoch)
+    fmt.Fprintf(&b, "timestamp  %d (%s, %s)\n", e.TimestampMillis, e.Time.Format(time.RFC3339Nano), e.AgeString())
+    fmt.Fprintf(&b, "region     %d\n", e.Region)
+    fmt.Fprintf(&b, "node       %d\n", e.Node)
+    fmt.Fprintf(&b, "sequence   %d", e.Sequence)
+    return b.String()
+}
+
+// AgeString 回傳約略的時間差，例如 "3m12s ago" 或 "250ms in the future"
+// 一秒以上取整到秒，以下取整到毫秒
+func (e Explanation) AgeString() string {
+    age, suffix := e.Age, "ago"
+    if age < 0 {
+        age, suffix = -age, "in the future"
+    }
+    if age >= time.Second {
+        age = age.Round(time.Second)
+    } else {
+        age = age.Round(time.Millisecond)
+    }
+    return age.String() + " " + suffix
+}
+
+// MarshalJSON 實作 json.Marshaler，額外輸出 age (AgeString) 與 age_ms
+func (e Explanation) MarshalJSON() ([]byte, error) {
+    type plain Explanation
+    return json.Marshal(struct {
+        plain
+        Age      string `json:"age"`
+        AgeMilli int64  `json:"age_ms"`
+    }{plain(e), e.AgeString(), e.Age.Milliseconds()})
+}
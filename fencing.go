@@ -0,0 +1,86 @@
+package idgen
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "math"
+    "math/bits"
+    "time"
+)
+
+// ------------- Fencing token ------------- //
+
+// fencingHorizon 為 fencing token 自起算點起至少須能表示的時間
+const fencingHorizon = 100 * 365 * 24 * time.Hour
+
+// WithFencingTokens 啟用 FencingToken 與 NextWithFencingToken
+// token 為 64 位元的 時間戳 << 計數器位元數 | 計數器，New 會檢查自起算點起 100 年內的時間戳都放得下，
+// 否則回傳錯誤：毫秒精度時計數器最多 22 位元，微秒精度時最多 12 位元 (預設 16 位元的序列號不適用微秒精度)
+func WithFencingTokens() Option {
+    return func(g *Generator) error {
+        g.fencing = true
+        return nil
+    }
+}
+
+// validateFencing 檢查 fencingHorizon 內的時間戳左移計數器位元數後不會超過 64 位元
+func (g *Generator) validateFencing() error {
+    need := bits.Len64(uint64(fencingHorizon / g.tick))
+    if int(g.counterBits())+need > 64 {
+        return fmt.Errorf("idgen: fencing tokens need %d timestamp bits at %v precision, %d-bit counter leaves %d",
+            need, g.tick, g.counterBits(), 64-int(g.counterBits()))
+    }
+    return nil
+}
+
+// FencingToken 回傳嚴格遞增的 uint64，可作為分散式鎖的 fencing token：
+// 儲存端只接受比上次更大的 token，即可拒絕鎖已過期的舊持有者寫入
+//
+// token 由時間戳與序列號組成 (時間戳 << 計數器位元數 | 計數器)，與 Next 共用同一份狀態並消耗一個序列號；
+// 時鐘回撥提升 epoch 後時間戳可能變小，此時改以上一個 token 加一，保證同一 Generator 內嚴格遞增
+// 設定 StateStore 時重啟後的 token 也會大於重啟前發出的 token；不同節點的 token 不保證互相有序
+// 需以 WithFencingTokens 建立 Generator，否則回傳錯誤
+func (g *Generator) FencingToken() (uint64, error) {
+    _, token, err := g.NextWithFencingToken()
+    return token, err
+}
+
+// NextWithFencingToken 產生一個 ID 並同時回傳對應的 fencing token
+func (g *Generator) NextWithFencingToken() (ID, uint64, error) {
+    if !g.fencing {
+        return ID{}, 0, errors.New("idgen: fencing tokens not enabled, create the generator with WithFencingTokens")
+    }
+    g.mu.Lock()
+    defer g.mu.Unlock()
+
+    id, err := g.nextLocked(context.Background(), g.currentMillis())
+    if err != nil {
+        return ID{}, 0, err
+    }
+    g.metrics.IncCounter(MetricIDsIssued, 1)
+
+    if g.lastMillis > math.MaxUint64>>g.counterBits() || g.lastToken == math.MaxUint64 {
+        return ID{}, 0, fmt.Errorf("idgen: fencing token overflows 64 bits at timestamp %d", g.lastMillis)
+    }
+    token := g.lastMillis<<g.counterBits() | uint64(g.sequence)
+    if token <= g.lastToken {
+        token = g.lastToken + 1
+    }
+    g.lastToken = token
+    return id, token, nil
+}
+
+// counterBits 回傳序列號欄位中計數器所占的位元數 (扣除隨機填入的低位)
+func (g *Generator) counterBits() uint8 {
+    return g.layout.SequenceBits - g.entropyBits
+}
+
+// tokenFloor 回傳時間戳不超過 lastMillis 的 token 上限，供重啟後延續 fencing token
+// 超出 64 位元時回傳 math.MaxUint64，之後的 FencingToken 會回報溢位而非回繞
+func (g *Generator) tokenFloor(lastMillis uint64) uint64 {
+    if lastMillis >= math.MaxUint64>>g.counterBits() {
+        return math.MaxUint64
+    }
+    return (lastMillis+1)<<g.counterBits() - 1
+}
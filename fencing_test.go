@@ -0,0 +1,62 @@
+package idgen_test
+
+import (
+    "testing"
+    "time"
+
+    "github.com/pascal910107/idgen"
+)
+
+func TestFencingTokenBitBudget(t *testing.T) {
+    tests := []struct {
+        name string
+        opts []idgen.Option
+        ok   bool
+    }{
+        {"default", nil, true},
+        {"microsecond", []idgen.Option{idgen.WithPrecision(time.Microsecond)}, false},
+        {"wide sequence", []idgen.Option{idgen.WithLayout(idgen.Layout{EpochBits: 16, TimestampBits: 64, RegionBits: 8, NodeBits: 10, SequenceBits: 30})}, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            g, err := idgen.New(append(tt.opts, idgen.WithFencingTokens())...)
+            if (err == nil) != tt.ok {
+                t.Fatalf("New error = %v, want ok %v", err, tt.ok)
+            }
+            if err == nil {
+                g.Release()
+            }
+        })
+    }
+}
+
+func TestFencingTokenRequiresOption(t *testing.T) {
+    g, err := idgen.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer g.Release()
+    if _, err := g.FencingToken(); err == nil {
+        t.Fatal("FencingToken succeeded without WithFencingTokens")
+    }
+}
+
+func TestFencingTokenMonotonic(t *testing.T) {
+    g, err := idgen.New(idgen.WithFencingTokens())
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer g.Release()
+
+    var prev uint64
+    for i := range 100_000 {
+        _, token, err := g.NextWithFencingToken()
+        if err != nil {
+            t.Fatal(err)
+        }
+        if token <= prev {
+            t.Fatalf("token %d: %d not after %d", i, token, prev)
+        }
+        prev = token
+    }
+}
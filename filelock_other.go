@@ -0,0 +1,10 @@
+//go:build !unix
+
+package idgen
+
+import "errors"
+
+// tryLockFile 在不支援 flock 的平台上一律回傳錯誤
+func tryLockFile(string) (func(), bool, error) {
+    return nil, false, errors.New("idgen: file lock provider is not supported on this platform")
+}
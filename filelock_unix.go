@@ -0,0 +1,28 @@
+//go:build unix
+
+package idgen
+
+import (
+    "errors"
+    "os"
+    "syscall"
+)
+
+// tryLockFile 以非阻塞 flock 取得 path 的獨占鎖，已被占用時回傳 ok=false
+func tryLockFile(path string) (release func(), ok bool, err error) {
+    f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+    if err != nil {
+        return nil, false, err
+    }
+    if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+        f.Close()
+        if errors.Is(err, syscall.EWOULDBLOCK) {
+            return nil, false, nil
+        }
+        return nil, false, err
+    }
+    return func() {
+        syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+        f.Close()
+    }, true, nil
+}
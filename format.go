@@ -0,0 +1,50 @@
+package idgen
+
+import (
+    "fmt"
+    "strings"
+    "time"
+)
+
+// ------------- fmt.Formatter ------------- //
+
+// Format 實作 fmt.Formatter：
+//
+//	%s %v  hex 字串 (同 String)
+//	%x %X  小寫 / 大寫 hex
+//	%q     加上雙引號的 hex 字串
+//	%+v    hex 加上解碼後各欄位，例如 0000...(epoch=0 time=2025-01-01T00:00:00Z region=1 node=2 seq=3)
+//	%#v    Go 語法表示
+//
+// 寬度與對齊旗標 (例如 %-40s) 照常套用
+func (id ID) Format(f fmt.State, verb rune) {
+    var s string
+    switch verb {
+    case 's', 'x', 'q':
+        s = id.Hex()
+    case 'X':
+        s = strings.ToUpper(id.Hex())
+    case 'v':
+        switch {
+        case f.Flag('#'):
+            fmt.Fprintf(f, "idgen.ID(%#v)", [16]byte(id))
+            return
+        case f.Flag('+'):
+            s = fmt.Sprintf("%s(epoch=%d time=%s region=%d node=%d seq=%d)",
+                id.Hex(), id.Epoch(), id.Time().UTC().Format(time.RFC3339Nano), id.Region(), id.Node(), id.Sequence())
+        default:
+            s = id.Hex()
+        }
+    default:
+        fmt.Fprintf(f, "%%!%c(idgen.ID=%s)", verb, id.Hex())
+        return
+    }
+
+    // 以字串重新套用原本的寬度與旗標
+    format := fmt.FormatString(f, verb)
+    if verb == 'q' {
+        fmt.Fprintf(f, format, s)
+        return
+    }
+    fmt.Fprintf(f, strings.NewReplacer("+", "", "#", "").Replace(format[:len(format)-1])+"s", s)
+}
@@ -0,0 +1,93 @@
+package idgen
+
+import (
+    "fmt"
+    "sync"
+)
+
+// ------------- Hybrid Logical Clock 產生器 ------------- //
+
+// HLCGenerator 以 Hybrid Logical Clock 產生 ID：timestamp 欄位為邏輯時間 l，sequence 欄位為計數器 c
+//
+// 與 Generator 的差異：
+//   - 邏輯時間永不倒退，時鐘回撥時沿用 l 並遞增 c，不需提升 epoch
+//   - Observe 收到遠端 ID 後，之後產生的 ID 必大於該遠端 ID，時鐘有偏差的節點間仍維持因果順序
+//   - c 用盡時直接將 l 加一 (借用下一毫秒)，不會睡眠
+//
+// 遠端時鐘超前時本地 l 會被帶著前進，timestamp 可能暫時領先本地實際時間
+// 不支援 StateStore、RollbackPolicy、SequencePolicy、Metrics、hooks、日誌等行為選項，指定時 NewHLC 回傳錯誤
+type HLCGenerator struct {
+    base        *Generator // 持有時鐘、NodeIDProvider 等資源，Release 時歸還
+    customEpoch int64
+    clock       Clock
+    regionID    uint16
+    nodeID      uint16
+
+    mu    sync.Mutex
+    epoch uint16
+    l     uint64 // 邏輯時間 (毫秒)
+    c     uint16 // 同一邏輯時間內的計數器
+}
+
+// NewHLC 以與 New 相同的選項建立 HLCGenerator
+func NewHLC(opts ...Option) (*HLCGenerator, error) {
+    g, err := New(opts...)
+    if err != nil {
+        return nil, err
+    }
+    err = g.requireDefaults("HLCGenerator")
+    if err == nil {
+        err = g.requireBasic("HLCGenerator")
+    }
+    if err != nil {
+        g.Release()
+        return nil, err
+    }
+    return &HLCGenerator{
+        base:        g,
+        customEpoch: g.customEpoch,
+        clock:       g.clock,
+        regionID:    uint16(g.regionID),
+        nodeID:      uint16(g.nodeID),
+        epoch:       g.epoch,
+    }, nil
+}
+
+// Release 歸還建立時由 NodeIDProvider 取得的資源並停止背景工作 (例如 WithCachedClock 的更新)
+func (h *HLCGenerator) Release() {
+    h.base.Release()
+}
+
+// Next 產生下一個 ID (thread‑safe)，必大於先前產生與 Observe 過的所有 ID
+func (h *HLCGenerator) Next() (ID, error) {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+
+    ms := h.clock.Now().UnixMilli() - h.customEpoch
+    if ms < 0 {
+        return ID{}, fmt.Errorf("%w: %d ms early", ErrClockBeforeEpoch, -ms)
+    }
+    pt := uint64(ms)
+    if pt > h.l {
+        h.l, h.c = pt, 0
+    } else if h.c == maxSequence {
+        h.l, h.c = h.l+1, 0
+    } else {
+        h.c++
+    }
+    return makeID(h.epoch, h.l, h.regionID, h.nodeID, h.c), nil
+}
+
+// Observe 合併遠端 ID 的邏輯時間 (thread‑safe)，通常於收到其他節點的訊息時呼叫
+// 由於 region/node 位於 timestamp 與 sequence 之間，同一邏輯時間內無法保證大於遠端 ID，
+// 因此遠端邏輯時間不小於本地時，下一個 ID 會進入遠端邏輯時間的下一毫秒 (或更晚的實際時間)
+// 遠端 epoch 較大時一併採用遠端的 epoch
+func (h *HLCGenerator) Observe(remote ID) {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+
+    re, rl := remote.Epoch(), remote.TimestampMillis()
+    if re > h.epoch || re == h.epoch && rl >= h.l {
+        h.epoch, h.l, h.c = re, rl, maxSequence // c 設為用盡，迫使下一個 ID 推進邏輯時間
+    }
+}
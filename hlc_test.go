@@ -0,0 +1,72 @@
+package idgen_test
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/pascal910107/idgen"
+)
+
+func TestNewHLCRejectsUnsupportedOptions(t *testing.T) {
+    tests := []struct {
+        name string
+        opt  idgen.Option
+    }{
+        {"rollback policy", idgen.WithRollbackPolicy(idgen.ReturnError())},
+        {"sequence policy", idgen.WithSequencePolicy(idgen.SequenceBorrow)},
+        {"metrics", idgen.WithMetrics(idgen.MetricsFuncs{})},
+        {"hooks", idgen.OnSequenceExhausted(func(uint64) {})},
+        {"journal", idgen.WithJournal(idgen.NewJournalWriter(nil))},
+        {"precision", idgen.WithPrecision(time.Microsecond)},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if h, err := idgen.NewHLC(tt.opt); err == nil {
+                h.Release()
+                t.Fatalf("NewHLC accepted %s", tt.name)
+            }
+        })
+    }
+}
+
+func TestHLCRelease(t *testing.T) {
+    released := false
+    h, err := idgen.NewHLC(idgen.WithNodeIDProvider(t.Context(), idgen.NodeIDProviderFunc(
+        func(context.Context) (uint16, uint16, func(), error) {
+            return 1, 2, func() { released = true }, nil
+        })))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if released {
+        t.Fatal("node id released before Release")
+    }
+    h.Release()
+    if !released {
+        t.Fatal("Release did not return the node id lease")
+    }
+}
+
+func TestHLCObserve(t *testing.T) {
+    h, err := idgen.NewHLC(idgen.WithRegionNode(0, 1))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer h.Release()
+
+    remote := idgen.FromParts(0, uint64(time.Hour.Milliseconds())+mustNext(t, h).TimestampMillis(), 0, 2, 7)
+    h.Observe(remote)
+    if id := mustNext(t, h); id.Compare(remote) <= 0 {
+        t.Fatalf("%s not after observed %s", id, remote)
+    }
+}
+
+func mustNext(t *testing.T, h *idgen.HLCGenerator) idgen.ID {
+    t.Helper()
+    id, err := h.Next()
+    if err != nil {
+        t.Fatal(err)
+    }
+    return id
+}
@@ -0,0 +1,70 @@
+package idgen
+
+import "time"
+
+// ------------- 事件回呼 ------------- //
+
+// hooks 保存 Generator 進入降級路徑時的回呼，未設定者為 nil
+type hooks struct {
+    rollback  func(drift time.Duration)
+    exhausted func(ts uint64)
+    bump      func(old, new uint16)
+}
+
+// OnClockRollback 在偵測到時鐘回撥時以回撥幅度呼叫 fn，於 RollbackPolicy 處理之前
+// 所有回呼皆在持有 Generator 內部鎖時同步執行，應盡快返回且不可再呼叫同一 Generator
+func OnClockRollback(fn func(drift time.Duration)) Option {
+    return func(g *Generator) error {
+        g.hooks.rollback = fn
+        return nil
+    }
+}
+
+// OnSequenceExhausted 在同一時間戳的序列號用盡時以該時間戳呼叫 fn，於 SequencePolicy 處理之前
+func OnSequenceExhausted(fn func(ts uint64)) Option {
+    return func(g *Generator) error {
+        g.hooks.exhausted = fn
+        return nil
+    }
+}
+
+// OnEpochBump 在 epoch 因時鐘回撥、重啟或 NextAfter 而提升時以新舊值呼叫 fn
+func OnEpochBump(fn func(old, new uint16)) Option {
+    return func(g *Generator) error {
+        g.hooks.bump = fn
+        return nil
+    }
+}
+
+// bumpEpoch 將 epoch 設為 next (依 layout 截斷)，回報指標、記錄日誌並呼叫回呼
+func (g *Generator) bumpEpoch(next uint16) {
+    old := g.epoch
+    g.epoch = next & g.layout.MaxEpoch()
+    g.metrics.IncCounter(MetricEpochBumps, 1)
+    if g.logger != nil {
+        g.logger.Warn("idgen: epoch bumped", "old", old, "new", g.epoch, "region", g.regionID, "node", g.nodeID)
+    }
+    if g.hooks.bump != nil {
+        g.hooks.bump(old, g.epoch)
+    }
+}
+
+// reportRollback 記錄偵測到的時鐘回撥並呼叫回呼
+func (g *Generator) reportRollback(drift time.Duration) {
+    if g.logger != nil {
+        g.logger.Warn("idgen: clock moved backwards", "drift", drift, "region", g.regionID, "node", g.nodeID)
+    }
+    if g.hooks.rollback != nil {
+        g.hooks.rollback(drift)
+    }
+}
+
+// reportExhausted 記錄序列號用盡並呼叫回呼；高負載下屬正常現象，因此只記錄為 Debug
+func (g *Generator) reportExhausted(ts uint64) {
+    if g.logger != nil {
+        g.logger.Debug("idgen: sequence exhausted", "timestamp", ts, "region", g.regionID, "node", g.nodeID)
+    }
+    if g.hooks.exhausted != nil {
+        g.hooks.exhausted(ts)
+    }
+}
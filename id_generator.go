@@ -14,18 +14,26 @@
 package idgen
 
 import (
+    "bytes"
+    "context"
+    "crypto/rand"
     "encoding/base64"
     "encoding/binary"
     "encoding/hex"
     "errors"
     "fmt"
+    "log/slog"
+    "math"
+    "runtime"
+    "strings"
     "sync"
     "time"
 )
 
 // ------------- 常量設定 ------------- //
 
-// CustomEpoch 定義時間戳起算點 (毫秒)，選用近期固定時間以縮短 timestamp 數值範圍
+// CustomEpoch 定義預設的時間戳起算點 (毫秒)，選用近期固定時間以縮短 timestamp 數值範圍
+// 僅於 New 建立 Generator 時讀取一次；個別 Generator 請改用 WithCustomEpoch 設定
 var CustomEpoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli()
 
 const (
@@ -63,120 +71,611 @@ func (id ID) Base64URL() string {
     return base64.RawURLEncoding.EncodeToString(id[:])
 }
 
+// AppendHex 將 Hex 字串附加到 dst 後回傳，dst 容量足夠時不配置記憶體
+func (id ID) AppendHex(dst []byte) []byte {
+    return hex.AppendEncode(dst, id[:])
+}
+
+// AppendBase64URL 將 Base64URL 字串附加到 dst 後回傳，dst 容量足夠時不配置記憶體
+func (id ID) AppendBase64URL(dst []byte) []byte {
+    return base64.RawURLEncoding.AppendEncode(dst, id[:])
+}
+
 // String 預設用 Hex 表示 (Implement fmt.Stringer)
 func (id ID) String() string { return id.Hex() }
 
-// Parse 解析 16‑byte 或 hex/base64 字串為 ID
+// Compare 依 bytes 字典序比較兩個 ID，回傳 -1、0 或 1
+// 此全序等同依 (Epoch, Timestamp, Region, Node, Sequence) 逐欄位比較，
+// 因此同一 Generator 產生的 ID 後者必大於前者
+func (id ID) Compare(other ID) int {
+    return bytes.Compare(id[:], other[:])
+}
+
+// Less 回傳 id 是否排在 other 之前
+func (id ID) Less(other ID) bool { return id.Compare(other) < 0 }
+
+// Equal 回傳兩個 ID 是否相同 (等同 id == other)
+func (id ID) Equal(other ID) bool { return id == other }
+
+// Parse 解析 16‑byte 或 hex/base64/base32/UUID/ULID 字串為 ID；帶檢查碼的 base32 會一併驗證檢查碼
+// 26 字元的字串可能是 ID 本身的 Crockford 編碼或 ULID，依 isULID 的規則區分，ULID 以 FromULID 轉換
 func Parse(s string) (ID, error) {
     var id ID
 
+    // 人工輸入時分組的 Base32Check (例如每 5 個字元一組，共 32 字元) 長度可能與 hex 或 UUID 相同，
+    // 先以去除分隔符號後的長度辨識；base64 與 UUID 去除後不會剛好是 27 個字元
+    if len(s) != 27 && strings.ContainsAny(s, "- ") && len(s)-strings.Count(s, "-")-strings.Count(s, " ") == 27 {
+        return ParseBase32Check(s)
+    }
+
     switch len(s) {
+    case 0: // 僅於 SetEmptyAsNil(true) 時視為 NilID
+        if EmptyAsNil() {
+            return NilID, nil
+        }
+        return id, fmt.Errorf("%w: empty id string", ErrInvalidLength)
     case 16: // 原始 bytes (UTF‑8 會破壞，僅限程式內部)
         copy(id[:], []byte(s))
         return id, nil
     case 22: // base64 URL‑safe (22 bytes 可還原 16 bytes)
         b, err := base64.RawURLEncoding.DecodeString(s)
         if err != nil {
-            return id, err
+            return id, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
         }
         if len(b) != 16 {
-            return id, errors.New("invalid base64 length")
+            return id, fmt.Errorf("%w: base64 decodes to %d bytes", ErrInvalidLength, len(b))
         }
         copy(id[:], b)
         return id, nil
+    case 26: // Crockford Base32 或 ULID (不分大小寫)
+        u, err := ParseBase32Crockford(s)
+        if err != nil || !isULID(u) {
+            return u, err
+        }
+        return FromULID(u)
+    case 27: // Crockford Base32 加檢查碼
+        return ParseBase32Check(s)
     case 32: // hex 編碼
         b, err := hex.DecodeString(s)
         if err != nil {
-            return id, err
+            return id, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
         }
         copy(id[:], b)
         return id, nil
+    case 36: // UUID 8-4-4-4-12
+        return parseUUID(s)
     default:
-        return id, fmt.Errorf("unsupported id string length %d", len(s))
+        if strings.ContainsAny(s, "- ") { // 人工輸入時分組的 Base32Check
+            return ParseBase32Check(s)
+        }
+        return id, fmt.Errorf("%w: unsupported id string length %d", ErrInvalidLength, len(s))
     }
 }
 
+// ParseBytes 同 Parse，但直接解碼 []byte 而不先轉成 string
+// 原始 16 bytes、hex 與 Base64URL 不配置記憶體，其餘格式交由 Parse 處理
+func ParseBytes(b []byte) (ID, error) {
+    var id ID
+
+    switch len(b) {
+    case 16:
+        copy(id[:], b)
+        return id, nil
+    case 22:
+        if _, err := base64.RawURLEncoding.Decode(id[:], b); err != nil {
+            return ID{}, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+        }
+        return id, nil
+    case 32:
+        if _, err := hex.Decode(id[:], b); err != nil {
+            return ID{}, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+        }
+        return id, nil
+    default:
+        return Parse(string(b))
+    }
+}
+
+// MustParse 同 Parse，解析失敗時 panic，適合套件層級變數與測試資料
+func MustParse(s string) ID {
+    id, err := Parse(s)
+    if err != nil {
+        panic(err)
+    }
+    return id
+}
+
 // Decode 欄位
 func (id ID) Decode() (epoch uint16, tsMillis uint64, regionID, nodeID, seq uint16) {
-    epoch = binary.BigEndian.Uint16(id[0:2])
-    tsMillis = binary.BigEndian.Uint64(id[2:10])
-    regionID = binary.BigEndian.Uint16(id[10:12])
-    nodeID = binary.BigEndian.Uint16(id[12:14])
-    seq = binary.BigEndian.Uint16(id[14:16])
-    return
+    return id.Epoch(), id.TimestampMillis(), id.Region(), id.Node(), id.Sequence()
 }
 
+// FromParts 以預設位元配置組出指定欄位的 ID，為 Decode 的反向操作，供測試、資料遷移與管理工具建構精確的 ID
+// 各欄位寬度與參數型別相同；ts 超過 math.MaxInt64 時無法換算為時間，視為呼叫端錯誤而 panic
+// 自訂位元配置請使用 Layout.FromParts
+func FromParts(epoch uint16, ts uint64, region, node, seq uint16) ID {
+    if ts > math.MaxInt64 {
+        panic(fmt.Sprintf("idgen: timestamp %d out of range", ts))
+    }
+    return makeID(epoch, ts, region, node, seq)
+}
+
+// DecodedID 為 ID 各欄位解碼後的結構化表示
+type DecodedID struct {
+    Epoch           uint16
+    TimestampMillis uint64
+    Region          uint16
+    Node            uint16
+    Sequence        uint16
+}
+
+// Decoded 回傳結構化的解碼結果，免去 Decode 多回傳值的位置對應
+func (id ID) Decoded() DecodedID {
+    return DecodedID{
+        Epoch:           id.Epoch(),
+        TimestampMillis: id.TimestampMillis(),
+        Region:          id.Region(),
+        Node:            id.Node(),
+        Sequence:        id.Sequence(),
+    }
+}
+
+// Epoch 回傳 epoch (時鐘回撥世代號) 欄位
+func (id ID) Epoch() uint16 { return binary.BigEndian.Uint16(id[0:2]) }
+
+// TimestampMillis 回傳自起算點起的毫秒時間戳欄位 (以 WithPrecision 設為微秒的 Generator 則為微秒數)
+func (id ID) TimestampMillis() uint64 { return binary.BigEndian.Uint64(id[2:10]) }
+
+// Region 回傳區域 ID 欄位
+func (id ID) Region() uint16 { return binary.BigEndian.Uint16(id[10:12]) }
+
+// Node 回傳節點 ID 欄位
+func (id ID) Node() uint16 { return binary.BigEndian.Uint16(id[12:14]) }
+
+// Sequence 回傳序列號欄位
+func (id ID) Sequence() uint16 { return binary.BigEndian.Uint16(id[14:16]) }
+
 // ------------- 產生器實作 ------------- //
 
 type Generator struct {
+    customEpoch int64 // 時間戳起算點 (Unix 毫秒)，建立後不再變動
+    clock       Clock // 時間來源，預設為系統時鐘
+    rollback    RollbackPolicy
+    releases    []func()      // 由 NodeIDProvider 取得的資源與背景工作，於 Release 時歸還或停止
+    starts      []func()      // New 完成設定後才啟動的背景工作，避免與 New 後續修改的欄位競爭
+    metrics     Metrics       // 建立後為包裝使用者 Metrics 的 &stats
+    stats       statsRecorder // Stats 的累計來源
+    seqPolicy   SequencePolicy
+    spinWait    time.Duration // 序列號用盡時自旋等待的上限，0 代表以 sleep 等待
+    hooks       hooks
+    journal     *JournalWriter // 可選，記錄每個發出的 ID
+    auditSink   AuditSink      // 可選，接收彙整後的發號紀錄
+    logger      *slog.Logger   // 可選，記錄回撥與 epoch 提升等事件
+    layout      Layout
+    entropyBits uint8         // 序列號低位以隨機值填入的位元數
+    privacy     timePrivacy   // 時間戳模糊化，預設不啟用
+    seqStart    seqStart      // 每個時間戳的序列號隨機起點，預設不啟用
+    tick        time.Duration // 時間戳單位 (精度)，預設毫秒；lastMillis 等欄位皆以此為單位
+    store       StateStore    // 可選的狀態持久化
+    startupBump bool          // 啟動時無條件提升 epoch
+    fencing     bool          // 已以 WithFencingTokens 啟用並驗證 fencing token
+    epochStore  StateStore    // 僅於啟動時保存 epoch 的 store，nil 代表沿用 store
+
     mu         sync.Mutex // 保護下列欄位的並發存取
-    regionID   uint16
-    nodeID     uint16
+    regionID   uint32     // 寬度依 layout，預設 16 bits
+    nodeID     uint32
     epoch      uint16
-    lastMillis uint64
-    sequence   uint16
+    lastMillis uint64 // 最近一個 ID 的時間戳
+    lastClock  uint64 // 最近一次實際觀測到的時鐘，借用下一毫秒時可能落後 lastMillis
+    sequence   uint32
+    persisted  State       // 最近一次寫入 store 的狀態
+    lastToken  uint64      // 最近一次發出的 fencing token
+    auditCur   AuditRecord // 尚未送出的稽核記錄，auditOpen 為 true 時有效
+    auditOpen  bool
+    auditNext  uint32 // auditCur 可接續的下一個計數器值
+    closed     bool   // 已呼叫 Close，之後的產生請求回傳 ErrGeneratorClosed
 }
 
-// NewGenerator 建立新的 Generator
-// 需指定唯一的 regionID 與 nodeID，範圍 0‑65535
-func NewGenerator(regionID, nodeID uint16) (*Generator, error) {
-    if regionID > maxRegion {
-        return nil, fmt.Errorf("region id %d 超出範圍 0‑%d", regionID, maxRegion)
+// New 以函式選項建立 Generator，未指定的欄位使用預設值
+func New(opts ...Option) (*Generator, error) {
+    g := &Generator{customEpoch: CustomEpoch, clock: SystemClock(), rollback: DefaultRollbackPolicy, metrics: nopMetrics{}, layout: DefaultLayout, tick: time.Millisecond}
+    if err := g.apply(opts); err != nil {
+        g.Release()
+        return nil, err
+    }
+    g.stats.next, g.metrics = g.metrics, &g.stats
+    if err := g.restore(); err != nil {
+        g.Release()
+        return nil, err
+    }
+    for _, start := range g.starts {
+        start()
+    }
+    g.starts = nil
+    return g, nil
+}
+
+// apply 依序套用選項並驗證結果
+func (g *Generator) apply(opts []Option) error {
+    for _, opt := range opts {
+        if err := opt(g); err != nil {
+            return err
+        }
+    }
+    if max := g.layout.MaxRegion(); g.regionID > max {
+        return fmt.Errorf("%w: %d not in 0-%d", ErrRegionOutOfRange, g.regionID, max)
+    }
+    if max := g.layout.MaxNode(); g.nodeID > max {
+        return fmt.Errorf("%w: %d not in 0-%d", ErrNodeOutOfRange, g.nodeID, max)
     }
-    if nodeID > maxNode {
-        return nil, fmt.Errorf("node id %d 超出範圍 0‑%d", nodeID, maxNode)
+    if g.entropyBits >= g.layout.SequenceBits {
+        return fmt.Errorf("idgen: %d entropy bits leave no room in %d-bit sequence", g.entropyBits, g.layout.SequenceBits)
+    }
+    if g.fencing {
+        if err := g.validateFencing(); err != nil {
+            return err
+        }
+    }
+    return g.privacy.validate(g.tick)
+}
+
+// requireDefaults 檢查 g 未使用 name 所代表的產生器不支援的選項 (位元配置、隨機位元、精度、時間戳模糊化)
+func (g *Generator) requireDefaults(name string) error {
+    switch {
+    case g.layout != DefaultLayout:
+        return fmt.Errorf("%w: %s supports only DefaultLayout", ErrInvalidLayout, name)
+    case g.entropyBits != 0:
+        return fmt.Errorf("idgen: %s does not support entropy bits", name)
+    case g.tick != time.Millisecond:
+        return fmt.Errorf("idgen: %s supports only millisecond precision", name)
+    case g.privacy.ticks != 0:
+        return fmt.Errorf("idgen: %s does not support timestamp privacy", name)
     }
-    return &Generator{regionID: regionID, nodeID: nodeID}, nil
+    return nil
+}
+
+// requireBasic 檢查 g 未使用 name 所代表的產生器無法遵守的行為選項：
+// AtomicGenerator 與 HLCGenerator 以自己的方式處理時鐘回撥與序列號用盡，不持久化狀態、不回報指標、不寫入日誌，
+// 這些選項若被默默忽略，呼叫端會誤以為仍受保護；WithStartupEpochBump 於建立時生效，仍可使用
+func (g *Generator) requireBasic(name string) error {
+    var opt string
+    switch {
+    case g.store != nil:
+        opt = "state store"
+    case g.rollback != DefaultRollbackPolicy:
+        opt = "rollback policy"
+    case g.seqPolicy != SequenceWait:
+        opt = "sequence policy"
+    case g.spinWait != 0:
+        opt = "spin wait"
+    case g.stats.next != Metrics(nopMetrics{}):
+        opt = "metrics"
+    case g.hooks.rollback != nil || g.hooks.exhausted != nil || g.hooks.bump != nil:
+        opt = "hooks"
+    case g.logger != nil:
+        opt = "logger"
+    default:
+        return g.requireUntracked(name)
+    }
+    return fmt.Errorf("idgen: %s does not support %s", name, opt)
+}
+
+// requireUntracked 檢查 g 未使用只有 Generator 本身會套用的發號選項：
+// 發號日誌、稽核記錄、隨機序列號起點與 fencing token，衍生的產生器不經過 Generator 的發號流程
+func (g *Generator) requireUntracked(name string) error {
+    var opt string
+    switch {
+    case g.journal != nil:
+        opt = "journal"
+    case g.auditSink != nil:
+        opt = "audit sink"
+    case g.seqStart.enabled:
+        opt = "random sequence start"
+    case g.fencing:
+        opt = "fencing tokens"
+    default:
+        return nil
+    }
+    return fmt.Errorf("idgen: %s does not support %s", name, opt)
+}
+
+// Release 歸還透過 NodeIDProvider 取得的節點 ID 並停止背景監控，之後不應再以此 Generator 產生 ID
+// 重複呼叫不會有副作用；需要拒絕後續請求並寫回狀態時改用 Close
+func (g *Generator) Release() {
+    g.mu.Lock()
+    releases := g.releases
+    g.releases = nil
+    g.mu.Unlock()
+
+    for i := len(releases) - 1; i >= 0; i-- {
+        releases[i]()
+    }
+}
+
+// NewGenerator 建立新的 Generator
+// 需指定唯一的 regionID 與 nodeID，範圍 0‑65535；等同 New(WithRegion(regionID), WithNode(nodeID))
+func NewGenerator(regionID, nodeID uint16) (*Generator, error) {
+    return New(WithRegion(regionID), WithNode(nodeID))
 }
 
 // Next 產生下一個唯一且有序的 ID (thread‑safe)
 func (g *Generator) Next() (ID, error) {
+    return g.NextContext(context.Background())
+}
+
+// NextContext 同 Next，但在時鐘回撥或序列號用盡需要等待時會遵守 ctx 的取消與期限
+// ctx 結束時立即回傳 ctx.Err()，不會產生 ID
+func (g *Generator) NextContext(ctx context.Context) (ID, error) {
+    if err := ctx.Err(); err != nil {
+        return ID{}, err
+    }
+
+    g.mu.Lock()
+    defer g.mu.Unlock()
+
+    id, err := g.nextLocked(ctx, g.currentMillis())
+    if err == nil {
+        g.metrics.IncCounter(MetricIDsIssued, 1)
+    }
+    return id, err
+}
+
+// NextN 一次產生 n 個遞增的 ID (thread‑safe)
+// 整批只取得一次鎖並只讀取一次時間，僅在序列號用盡時才重新讀取時鐘；設定 WithJournal 時整批寫為一次記錄
+func (g *Generator) NextN(n int) ([]ID, error) {
+    if n < 0 {
+        return nil, fmt.Errorf("%w: invalid id count %d", ErrInvalidArgument, n)
+    }
+
     g.mu.Lock()
     defer g.mu.Unlock()
 
-    now := uint64(time.Now().UnixMilli() - CustomEpoch)
-
-    // 時鐘回撥處理
-    if now < g.lastMillis {
-        // 若回撥幅度小 (< 5ms)，等待時間追上；否則升級 epoch
-        drift := g.lastMillis - now
-        if drift <= 5 {
-            time.Sleep(time.Duration(drift) * time.Millisecond)
-            now = uint64(time.Now().UnixMilli() - CustomEpoch)
-            if now < g.lastMillis { // 還是無法追上，保險做 epoch++
-                g.epoch = (g.epoch + 1) & maxEpoch
+    ids := make([]ID, n)
+    now := g.currentMillis()
+    for i := range ids {
+        id, err := g.issueLocked(context.Background(), now)
+        if err != nil {
+            return nil, err
+        }
+        ids[i] = id
+        now = g.lastClock // 沿用本次時間，同毫秒內只遞增序列號
+    }
+    if err := g.logIssued(ids...); err != nil {
+        return nil, err
+    }
+    g.metrics.IncCounter(MetricIDsIssued, uint64(n))
+    return ids, nil
+}
+
+// NextAfter 產生一個保證大於 after 的 ID (thread‑safe)，可用於 fencing token 或嚴格有序的事件鏈
+// after 來自其他節點或時鐘較快的來源時，會以實際時間或提升 epoch 超越，而不等待時鐘追上
+func (g *Generator) NextAfter(after ID) (ID, error) {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    if g.privacy.ticks != 0 { // 模糊化後的時間戳無法與 after 比較
+        return ID{}, errors.New("idgen: NextAfter does not support timestamp privacy")
+    }
+
+    id, err := g.nextLocked(context.Background(), g.currentMillis())
+    if err != nil || id.Compare(after) > 0 {
+        if err == nil {
+            g.metrics.IncCounter(MetricIDsIssued, 1)
+        }
+        return id, err
+    }
+
+    // 走到這裡代表 after 的 epoch 不小於目前 epoch；同 epoch 下實際時間已超越即可沿用，否則提升 epoch
+    now := g.currentMillis()
+    if now > math.MaxInt64 {
+        return ID{}, g.beforeEpochError()
+    }
+    af := g.layout.Decode(after)
+    switch ae := af.Epoch; {
+    case now > af.TimestampMillis:
+        g.epoch = ae
+    case ae < g.layout.MaxEpoch():
+        g.bumpEpoch(ae + 1)
+    default:
+        return ID{}, fmt.Errorf("idgen: cannot generate id after %v: epoch exhausted", after)
+    }
+    if err := g.persist(now); err != nil {
+        return ID{}, err
+    }
+    g.lastMillis, g.lastClock, g.sequence = now, now, 0
+    id = g.makeID(now)
+    if err := g.logIssued(id); err != nil {
+        return ID{}, err
+    }
+    g.audit(now)
+    g.metrics.IncCounter(MetricIDsIssued, 1)
+    return id, nil
+}
+
+// nextLocked 以 now 為目前時間產生 ID 並寫入日誌，呼叫端須持有 g.mu
+func (g *Generator) nextLocked(ctx context.Context, now uint64) (ID, error) {
+    id, err := g.issueLocked(ctx, now)
+    if err != nil {
+        return ID{}, err
+    }
+    if err := g.logIssued(id); err != nil {
+        return ID{}, err
+    }
+    return id, nil
+}
+
+// issueLocked 以 now 為目前時間產生 ID，不寫入日誌；批次產生時由呼叫端整批寫入，呼叫端須持有 g.mu
+func (g *Generator) issueLocked(ctx context.Context, now uint64) (ID, error) {
+    if g.closed {
+        return ID{}, ErrGeneratorClosed
+    }
+    if now > math.MaxInt64 { // 時鐘早於起算點，相減結果為負
+        return ID{}, g.beforeEpochError()
+    }
+
+    // 時鐘回撥處理：依 RollbackPolicy 等待、提升 epoch 或回報錯誤
+    // 以實際觀測到的時鐘 lastClock 判斷，借用下一毫秒造成的邏輯時間領先不算回撥
+    bumped := false
+    if now < g.lastClock {
+        g.metrics.IncCounter(MetricClockRollbacks, 1)
+        drift := time.Duration(g.lastClock-now) * g.tick
+        g.reportRollback(drift)
+        if drift <= g.rollback.maxWait {
+            start := g.clock.Now()
+            err := g.sleep(ctx, drift)
+            g.metrics.ObserveDuration(MetricWaitDuration, g.clock.Now().Sub(start))
+            if err != nil {
+                return ID{}, err
+            }
+            now = g.currentMillis()
+        }
+        if now < g.lastClock { // 仍無法追上
+            if g.rollback.fail {
+                return ID{}, fmt.Errorf("%w by %v", ErrClockRollback, time.Duration(g.lastClock-now)*g.tick)
             }
-        } else {
-            g.epoch = (g.epoch + 1) & maxEpoch
-            now = g.lastMillis // 確保值不減小
+            // 提升 epoch 後 ID 整體值必大於先前，時間戳可從目前時間繼續
+            g.bumpEpoch(g.epoch + 1)
+            bumped = true
         }
     }
 
-    if now == g.lastMillis {
-        g.sequence++
-        if g.sequence > maxSequence {
-            // 序列號溢出：等待下一毫秒
+    clock := now
+    switch {
+    case bumped:
+        g.sequence = 0
+    case now <= g.lastMillis:
+        now = g.lastMillis // 同一毫秒，或借用後邏輯時間仍領先實際時間
+        if g.sequence < g.layout.MaxSequence()>>g.entropyBits {
+            g.sequence++
+            break
+        }
+
+        // 序列號溢出 (先判斷再遞增，避免回繞成 0 造成重複)
+        g.metrics.IncCounter(MetricSequenceExhausted, 1)
+        g.reportExhausted(now)
+        switch g.seqPolicy {
+        case SequenceFail:
+            return ID{}, fmt.Errorf("%w at %d", ErrSequenceExhausted, now)
+        case SequenceBorrow:
+            now++
+        default: // SequenceWait：等待下一個時間單位
+            start := g.clock.Now()
             for now <= g.lastMillis {
-                time.Sleep(time.Millisecond)
-                now = uint64(time.Now().UnixMilli() - CustomEpoch)
+                if err := g.waitTick(ctx, g.tick); err != nil {
+                    g.metrics.ObserveDuration(MetricWaitDuration, g.clock.Now().Sub(start))
+                    return ID{}, err
+                }
+                now = g.currentMillis()
             }
-            g.sequence = 0
+            g.metrics.ObserveDuration(MetricWaitDuration, g.clock.Now().Sub(start))
+            clock = now
         }
-    } else {
         g.sequence = 0
+    default:
+        g.sequence = 0
+    }
+
+    if now > math.MaxInt64 { // 等待期間時鐘被調回起算點之前
+        return ID{}, g.beforeEpochError()
     }
+    if now > g.layout.MaxTimestamp() {
+        return ID{}, fmt.Errorf("idgen: timestamp %d overflows %d-bit layout", now, g.layout.TimestampBits)
+    }
+    if err := g.persist(now); err != nil {
+        return ID{}, err
+    }
+    g.lastMillis, g.lastClock = now, clock
+
+    id := g.makeID(now)
+    g.audit(now)
+    return id, nil
+}
+
+// makeID 以目前的 epoch、節點與序列號依 g.layout 組出時間戳為 ts 的 ID，呼叫端須持有 g.mu
+// 啟用 entropyBits 時序列號欄位為 計數器<<entropyBits | 隨機值；計數器先依 seqStart 平移
+func (g *Generator) makeID(ts uint64) ID {
+    seq := g.seqStart.apply(ts, g.sequence, g.layout.MaxSequence()>>g.entropyBits)
+    ts = g.privacy.embed(ts)
+    if g.entropyBits > 0 {
+        var b [4]byte
+        rand.Read(b[:])
+        seq = seq<<g.entropyBits | binary.BigEndian.Uint32(b[:])&uint32(mask64(g.entropyBits))
+    }
+    if g.layout == DefaultLayout {
+        return makeID(g.epoch, ts, uint16(g.regionID), uint16(g.nodeID), uint16(seq))
+    }
+    return g.layout.Encode(LayoutFields{Epoch: g.epoch, TimestampMillis: ts, Region: g.regionID, Node: g.nodeID, Sequence: seq})
+}
 
-    g.lastMillis = now
+// Layout 回傳此 Generator 的位元配置
+func (g *Generator) Layout() Layout {
+    return g.layout
+}
+
+// Decode 依此 Generator 的位元配置拆解 id
+func (g *Generator) Decode(id ID) LayoutFields {
+    return g.layout.Decode(id)
+}
 
-    // 組裝 ID (Big‑Endian)：
+// makeID 依 Big‑Endian 結構組裝 ID
+func makeID(epoch uint16, tsMillis uint64, regionID, nodeID, seq uint16) ID {
     var id ID
-    binary.BigEndian.PutUint16(id[0:2], g.epoch)
-    binary.BigEndian.PutUint64(id[2:10], now)
-    binary.BigEndian.PutUint16(id[10:12], g.regionID)
-    binary.BigEndian.PutUint16(id[12:14], g.nodeID)
-    binary.BigEndian.PutUint16(id[14:16], g.sequence)
+    binary.BigEndian.PutUint16(id[0:2], epoch)
+    binary.BigEndian.PutUint64(id[2:10], tsMillis)
+    binary.BigEndian.PutUint16(id[10:12], regionID)
+    binary.BigEndian.PutUint16(id[12:14], nodeID)
+    binary.BigEndian.PutUint16(id[14:16], seq)
+    return id
+}
 
-    return id, nil
+// sleep 透過 clock 等待 d；ctx 可取消時改為每毫秒檢查一次，被取消即回傳 ctx.Err()
+func (g *Generator) sleep(ctx context.Context, d time.Duration) error {
+    if ctx.Done() == nil { // 不可取消的 ctx，直接整段等待
+        g.clock.Sleep(d)
+        return nil
+    }
+    for d > 0 {
+        if err := ctx.Err(); err != nil {
+            return err
+        }
+        step := min(d, time.Millisecond)
+        g.clock.Sleep(step)
+        d -= step
+    }
+    return ctx.Err()
+}
+
+// waitTick 等待時鐘進入下一個長度為 d 的時間單位
+// 預設 sleep 整個 d；設定 WithSpinWait 時改以自旋等待至下一個單位的起點，超過 g.spinWait 後退回 sleep
+// 自旋上限以實際時間計算，時間來源停滯 (例如測試用的假時鐘) 時不會無限自旋
+func (g *Generator) waitTick(ctx context.Context, d time.Duration) error {
+    if g.spinWait <= 0 {
+        return g.sleep(ctx, d)
+    }
+    next := g.clock.Now().Truncate(d).Add(d)
+    deadline := time.Now().Add(g.spinWait)
+    for g.clock.Now().Before(next) {
+        if err := ctx.Err(); err != nil {
+            return err
+        }
+        if time.Now().After(deadline) {
+            return g.sleep(ctx, d)
+        }
+        runtime.Gosched()
+    }
+    return nil
+}
+
+// beforeEpochError 回傳包含目前時間與起算點的 ErrClockBeforeEpoch
+func (g *Generator) beforeEpochError() error {
+    return fmt.Errorf("%w: now %v, epoch %v", ErrClockBeforeEpoch, g.clock.Now().UTC(), time.UnixMilli(g.customEpoch).UTC())
+}
+
+// currentMillis 回傳相對起算點的時間戳 (單位為 g.tick)；時鐘早於起算點時結果會大於 math.MaxInt64
+func (g *Generator) currentMillis() uint64 {
+    if g.tick == time.Microsecond {
+        return uint64(g.clock.Now().UnixMicro() - g.customEpoch*1000)
+    }
+    return uint64(g.clock.Now().UnixMilli() - g.customEpoch)
 }
 
 // ------------- 使用範例 ------------- //
@@ -0,0 +1,102 @@
+package idgen_test
+
+import (
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/pascal910107/idgen"
+    "github.com/pascal910107/idgen/testclock"
+)
+
+func TestClockBeforeEpoch(t *testing.T) {
+    epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+    early := epoch.Add(-time.Hour)
+
+    tests := []struct {
+        name string
+        next func(clk idgen.Clock) (idgen.ID, error)
+    }{
+        {"Next", func(clk idgen.Clock) (idgen.ID, error) {
+            g, err := idgen.New(idgen.WithClock(clk), idgen.WithCustomEpoch(epoch))
+            if err != nil {
+                return idgen.ID{}, err
+            }
+            return g.Next()
+        }},
+        {"NextN", func(clk idgen.Clock) (idgen.ID, error) {
+            g, err := idgen.New(idgen.WithClock(clk), idgen.WithCustomEpoch(epoch))
+            if err != nil {
+                return idgen.ID{}, err
+            }
+            ids, err := g.NextN(3)
+            if len(ids) > 0 {
+                return ids[0], err
+            }
+            return idgen.ID{}, err
+        }},
+        {"Atomic", func(clk idgen.Clock) (idgen.ID, error) {
+            a, err := idgen.NewAtomic(idgen.WithClock(clk), idgen.WithCustomEpoch(epoch))
+            if err != nil {
+                return idgen.ID{}, err
+            }
+            defer a.Release()
+            return a.Next()
+        }},
+        {"HLC", func(clk idgen.Clock) (idgen.ID, error) {
+            h, err := idgen.NewHLC(idgen.WithClock(clk), idgen.WithCustomEpoch(epoch))
+            if err != nil {
+                return idgen.ID{}, err
+            }
+            defer h.Release()
+            return h.Next()
+        }},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            id, err := tt.next(testclock.New(early))
+            if !errors.Is(err, idgen.ErrClockBeforeEpoch) {
+                t.Fatalf("err = %v, want ErrClockBeforeEpoch", err)
+            }
+            if id != (idgen.ID{}) {
+                t.Fatalf("got id %s with error", id)
+            }
+        })
+    }
+}
+
+func TestClockFallsBeforeEpochAfterIssuing(t *testing.T) {
+    epoch := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+    g, clk, err := testclock.NewGenerator(epoch.Add(time.Second), idgen.WithCustomEpoch(epoch))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if _, err := g.Next(); err != nil {
+        t.Fatal(err)
+    }
+
+    clk.Set(epoch.Add(-time.Minute))
+    if _, err := g.Next(); !errors.Is(err, idgen.ErrClockBeforeEpoch) {
+        t.Fatalf("err = %v, want ErrClockBeforeEpoch", err)
+    }
+
+    clk.Set(epoch.Add(2 * time.Second))
+    id, err := g.Next()
+    if err != nil {
+        t.Fatalf("generator did not recover once the clock passed the epoch: %v", err)
+    }
+    if got := id.TimestampMillis(); got != 2000 {
+        t.Fatalf("timestamp = %d, want 2000", got)
+    }
+}
+
+func TestNextNNegativeCount(t *testing.T) {
+    g, err := idgen.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer g.Release()
+    if _, err := g.NextN(-1); !errors.Is(err, idgen.ErrInvalidArgument) {
+        t.Fatalf("NextN(-1) error = %v, want ErrInvalidArgument", err)
+    }
+}
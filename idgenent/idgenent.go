@@ -0,0 +1,47 @@
+// Package idgenent 提供在 ent schema 中以 idgen.ID 作為欄位 (含主鍵) 的輔助函式
+//
+// idgen.ID 本身即實作 driver.Valuer 與 sql.Scanner，可直接作為 field.Bytes 的 GoType；
+// 此套件不依賴 ent，只提供 DefaultFunc 與 SchemaType 所需的值：
+//
+//    func (Order) Fields() []ent.Field {
+//        return []ent.Field{
+//            field.Bytes("id").
+//                GoType(idgen.ID{}).
+//                SchemaType(idgenent.SchemaType).
+//                DefaultFunc(idgenent.Default(gen)).
+//                Immutable(),
+//        }
+//    }
+//
+// Postgres 的 uuid 欄位需搭配 idgen.SetSQLFormat(idgen.FormatUUID)
+package idgenent
+
+import (
+    "fmt"
+
+    "github.com/pascal910107/idgen"
+)
+
+// Source 為可產生 ID 的來源，*idgen.Generator、*idgen.IDPool 與 *idgen.ShardedGenerator 皆滿足
+type Source interface {
+    Next() (idgen.ID, error)
+}
+
+// SchemaType 為各方言的欄位型別，鍵值與 ent 的 dialect 常數相同
+var SchemaType = map[string]string{
+    "mysql":    "binary(16)",
+    "postgres": "uuid",
+    "sqlite3":  "blob",
+}
+
+// Default 回傳供 field.DefaultFunc 使用的函式，每次呼叫自 src 取得新 ID
+// ent 的預設值函式無法回傳錯誤，src 失敗 (例如時鐘回撥超過等待上限) 時會 panic
+func Default(src Source) func() idgen.ID {
+    return func() idgen.ID {
+        id, err := src.Next()
+        if err != nil {
+            panic(fmt.Sprintf("idgenent: generate default id: %v", err))
+        }
+        return id
+    }
+}
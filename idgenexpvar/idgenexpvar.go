@@ -0,0 +1,59 @@
+// Package idgenexpvar 以標準函式庫的 expvar 發布 Generator 的統計，供以 /debug/vars 收集指標而非執行 Prometheus 的部署使用
+//
+//    g, _ := idgen.New(idgen.WithRegion(1), idgen.WithNode(42))
+//    idgenexpvar.Publish("orders_idgen", g)
+//    // GET /debug/vars → "orders_idgen.ids_issued": 1234, ...
+//
+// 引入本套件會經由 expvar 在 http.DefaultServeMux 註冊 /debug/vars
+package idgenexpvar
+
+import (
+    "errors"
+    "expvar"
+    "fmt"
+
+    "github.com/pascal910107/idgen"
+)
+
+// DefaultPrefix 為 prefix 為空時使用的名稱前綴
+const DefaultPrefix = "idgen"
+
+// Source 為提供統計快照的來源，*idgen.Generator 與 *idgen.ShardedGenerator 皆實作此介面
+type Source interface {
+    Stats() idgen.Stats
+}
+
+// 發布的變數名稱 (加上 "<prefix>." 前綴) 與取值方式
+var fields = []struct {
+    name  string
+    value func(idgen.Stats) any
+}{
+    {"ids_issued", func(s idgen.Stats) any { return s.IDsIssued }},
+    {"clock_rollbacks", func(s idgen.Stats) any { return s.ClockRollbacks }},
+    {"epoch_bumps", func(s idgen.Stats) any { return s.EpochBumps }},
+    {"sequence_exhausted", func(s idgen.Stats) any { return s.SequenceExhausted }},
+    {"epoch", func(s idgen.Stats) any { return s.Epoch }},
+    {"last_timestamp_ms", func(s idgen.Stats) any { return s.LastMillis }},
+}
+
+// Publish 以 "<prefix>.ids_issued"、"<prefix>.clock_rollbacks"、"<prefix>.epoch_bumps"、
+// "<prefix>.sequence_exhausted"、"<prefix>.epoch" 與 "<prefix>.last_timestamp_ms" 發布 src 的統計
+// 數值於每次讀取 /debug/vars 時即時取得；任一名稱已被發布時不發布任何變數並回傳錯誤 (expvar 無法取消發布)
+func Publish(prefix string, src Source) error {
+    if src == nil {
+        return errors.New("idgenexpvar: nil source")
+    }
+    if prefix == "" {
+        prefix = DefaultPrefix
+    }
+    for _, f := range fields {
+        if name := prefix + "." + f.name; expvar.Get(name) != nil {
+            return fmt.Errorf("idgenexpvar: %s already published", name)
+        }
+    }
+    for _, f := range fields {
+        value := f.value
+        expvar.Publish(prefix+"."+f.name, expvar.Func(func() any { return value(src.Stats()) }))
+    }
+    return nil
+}
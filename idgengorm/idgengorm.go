@@ -0,0 +1,130 @@
+// Package idgengorm 讓 idgen.ID 可直接作為 GORM 模型欄位，不需自行撰寫 Valuer / Scanner
+//
+// 以 ID 型別宣告欄位時，AutoMigrate 會依方言建立欄位型別 (Postgres uuid、SQLite blob，
+// 其餘為 binary(16))，寫入時亦依方言送出對應表示：
+//
+//    type Order struct {
+//        ID   idgengorm.ID `gorm:"primaryKey"`
+//        Name string
+//    }
+//
+// 已使用 idgen.ID 的既有結構可改用 "idgen" serializer，欄位型別需以 type 標籤指定：
+//
+//    type Order struct {
+//        ID idgen.ID `gorm:"primaryKey;type:binary(16);serializer:idgen"`
+//    }
+package idgengorm
+
+import (
+    "context"
+    "database/sql/driver"
+    "fmt"
+    "reflect"
+
+    "github.com/pascal910107/idgen"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+    "gorm.io/gorm/schema"
+)
+
+// SerializerName 為註冊給 GORM 的 serializer 名稱
+const SerializerName = "idgen"
+
+func init() {
+    schema.RegisterSerializer(SerializerName, Serializer{})
+}
+
+// ------------- 自訂資料型別 ------------- //
+
+// ID 為可直接用於 GORM 模型的 idgen.ID
+type ID idgen.ID
+
+// ID 回傳對應的 idgen.ID
+func (id ID) ID() idgen.ID { return idgen.ID(id) }
+
+// String 回傳 hex 字串
+func (id ID) String() string { return idgen.ID(id).Hex() }
+
+// MarshalJSON 與 idgen.ID 相同，依 idgen.JSONFormat 輸出
+func (id ID) MarshalJSON() ([]byte, error) { return idgen.ID(id).MarshalJSON() }
+
+// UnmarshalJSON 與 idgen.ID 相同
+func (id *ID) UnmarshalJSON(b []byte) error { return (*idgen.ID)(id).UnmarshalJSON(b) }
+
+// Value 實作 driver.Valuer，行為同 idgen.ID.Value
+// 透過 GORM 寫入時會優先使用 GormValue
+func (id ID) Value() (driver.Value, error) { return idgen.ID(id).Value() }
+
+// Scan 實作 sql.Scanner，接受 16-byte 原始值或 uuid、hex 等文字
+func (id *ID) Scan(src any) error { return (*idgen.ID)(id).Scan(src) }
+
+// GormDataType 實作 schema.GormDataTypeInterface
+func (ID) GormDataType() string { return "idgen_id" }
+
+// GormDBDataType 依方言回傳 AutoMigrate 使用的欄位型別
+func (ID) GormDBDataType(db *gorm.DB, _ *schema.Field) string {
+    switch db.Dialector.Name() {
+    case "postgres":
+        return "uuid"
+    case "sqlite":
+        return "blob"
+    default:
+        return "binary(16)"
+    }
+}
+
+// GormValue 依方言送出寫入值：Postgres 為 uuid 字串，其餘為 16-byte 原始值
+// 不受 idgen.SetSQLFormat 影響，與 GormDBDataType 建立的欄位型別一致
+func (id ID) GormValue(_ context.Context, db *gorm.DB) clause.Expr {
+    v := idgen.ID(id)
+    if v.IsNil() && idgen.EmptyAsNil() {
+        return clause.Expr{SQL: "?", Vars: []any{nil}}
+    }
+    if db.Dialector.Name() == "postgres" {
+        return clause.Expr{SQL: "?", Vars: []any{v.UUIDString()}}
+    }
+    return clause.Expr{SQL: "?", Vars: []any{v.Bytes()}}
+}
+
+// ------------- Serializer ------------- //
+
+// Serializer 實作 schema.SerializerInterface，讓 idgen.ID 欄位以 serializer:idgen 標籤存取
+// 寫入格式依 idgen.SQLFormat (預設為 16-byte 原始值)
+type Serializer struct{}
+
+// Scan 將資料庫值解析後寫入 idgen.ID 或 *idgen.ID 欄位
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue any) error {
+    var id idgen.ID
+    if err := id.Scan(dbValue); err != nil {
+        return err
+    }
+    fv := field.ReflectValueOf(ctx, dst)
+    switch field.FieldType {
+    case reflect.TypeOf(id):
+        fv.Set(reflect.ValueOf(id))
+    case reflect.TypeOf(&id):
+        if dbValue == nil {
+            fv.Set(reflect.Zero(field.FieldType))
+        } else {
+            fv.Set(reflect.ValueOf(&id))
+        }
+    default:
+        return fmt.Errorf("idgengorm: serializer does not support field type %v", field.FieldType)
+    }
+    return nil
+}
+
+// Value 將 idgen.ID 或 *idgen.ID 欄位轉為資料庫值，nil 指標寫入 NULL
+func (Serializer) Value(_ context.Context, _ *schema.Field, _ reflect.Value, fieldValue any) (any, error) {
+    switch v := fieldValue.(type) {
+    case idgen.ID:
+        return v.Value()
+    case *idgen.ID:
+        if v == nil {
+            return nil, nil
+        }
+        return v.Value()
+    default:
+        return nil, fmt.Errorf("idgengorm: serializer does not support field type %T", fieldValue)
+    }
+}
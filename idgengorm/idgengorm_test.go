@@ -0,0 +1,110 @@
+package idgengorm_test
+
+import (
+    "bytes"
+    "context"
+    "reflect"
+    "sync"
+    "testing"
+
+    "github.com/pascal910107/idgen"
+    "github.com/pascal910107/idgen/idgengorm"
+    "gorm.io/gorm"
+    "gorm.io/gorm/clause"
+    "gorm.io/gorm/logger"
+    "gorm.io/gorm/schema"
+)
+
+// dialect 為只提供名稱的 gorm.Dialector，用於檢查依方言決定的行為
+type dialect string
+
+func (d dialect) Name() string                                 { return string(d) }
+func (dialect) Initialize(*gorm.DB) error                      { return nil }
+func (dialect) Migrator(*gorm.DB) gorm.Migrator                { return nil }
+func (dialect) DataTypeOf(*schema.Field) string                { return "" }
+func (dialect) DefaultValueOf(*schema.Field) clause.Expression { return nil }
+func (dialect) BindVarTo(clause.Writer, *gorm.Statement, any)  {}
+func (dialect) QuoteTo(clause.Writer, string)                  {}
+func (dialect) Explain(sql string, _ ...any) string            { return sql }
+
+func dbFor(name string) *gorm.DB {
+    return &gorm.DB{Config: &gorm.Config{Dialector: dialect(name), Logger: logger.Discard}}
+}
+
+func TestIDByDialect(t *testing.T) {
+    id := idgen.FromParts(1, 123456789, 2, 3, 4)
+    tests := []struct {
+        dialect string
+        column  string
+        value   any
+    }{
+        {"postgres", "uuid", id.UUIDString()},
+        {"sqlite", "blob", id.Bytes()},
+        {"mysql", "binary(16)", id.Bytes()},
+    }
+    for _, tt := range tests {
+        t.Run(tt.dialect, func(t *testing.T) {
+            db := dbFor(tt.dialect)
+            if got := (idgengorm.ID{}).GormDBDataType(db, nil); got != tt.column {
+                t.Fatalf("GormDBDataType = %q, want %q", got, tt.column)
+            }
+            expr := idgengorm.ID(id).GormValue(context.Background(), db)
+            if len(expr.Vars) != 1 {
+                t.Fatalf("GormValue vars = %v", expr.Vars)
+            }
+            switch want := tt.value.(type) {
+            case string:
+                if expr.Vars[0] != want {
+                    t.Fatalf("GormValue = %v, want %q", expr.Vars[0], want)
+                }
+            case []byte:
+                if got, ok := expr.Vars[0].([]byte); !ok || !bytes.Equal(got, want) {
+                    t.Fatalf("GormValue = %v, want %x", expr.Vars[0], want)
+                }
+            }
+
+            var scanned idgengorm.ID
+            if err := scanned.Scan(expr.Vars[0]); err != nil || scanned.ID() != id {
+                t.Fatalf("Scan(%v) = %s, %v, want %s", expr.Vars[0], scanned, err, id)
+            }
+        })
+    }
+}
+
+type order struct {
+    ID     idgen.ID  `gorm:"primaryKey;type:binary(16);serializer:idgen"`
+    Parent *idgen.ID `gorm:"type:binary(16);serializer:idgen"`
+}
+
+func TestSerializer(t *testing.T) {
+    s, err := schema.Parse(&order{}, &sync.Map{}, schema.NamingStrategy{})
+    if err != nil {
+        t.Fatal(err)
+    }
+    ctx := context.Background()
+    id := idgen.FromParts(1, 123456789, 2, 3, 4)
+
+    var o order
+    dst := reflect.ValueOf(&o).Elem()
+    for _, name := range []string{"ID", "Parent"} {
+        f := s.LookUpField(name)
+        v, err := f.Serializer.Value(ctx, f, dst, id)
+        if err != nil {
+            t.Fatal(err)
+        }
+        if err := f.Serializer.Scan(ctx, f, dst, v); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if o.ID != id || o.Parent == nil || *o.Parent != id {
+        t.Fatalf("scanned %+v, want both fields %s", o, id)
+    }
+
+    f := s.LookUpField("Parent")
+    if v, err := f.Serializer.Value(ctx, f, dst, (*idgen.ID)(nil)); err != nil || v != nil {
+        t.Fatalf("Value(nil pointer) = %v, %v, want NULL", v, err)
+    }
+    if err := f.Serializer.Scan(ctx, f, dst, nil); err != nil || o.Parent != nil {
+        t.Fatalf("Scan(NULL) left Parent = %v, %v", o.Parent, err)
+    }
+}
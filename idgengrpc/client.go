@@ -0,0 +1,78 @@
+package idgengrpc
+
+import (
+    "context"
+    "fmt"
+    "sync"
+
+    "github.com/pascal910107/idgen"
+    "google.golang.org/grpc"
+    "google.golang.org/protobuf/types/known/emptypb"
+    "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// Client 呼叫遠端 IDGenerator 服務 (thread‑safe)
+//
+// LeaseSize 大於 1 時啟用批次租用：Next 一次向服務端取得 LeaseSize 個 ID 快取於本地，
+// 用完才再次請求。租用的 ID 仍全域唯一，但其時間戳為租用當下，
+// 且與其他客戶端交錯使用時不再嚴格依發放時間排序
+type Client struct {
+    cc grpc.ClientConnInterface
+
+    // LeaseSize 為每次向服務端租用的 ID 數量，0 或 1 代表不快取
+    LeaseSize int
+
+    mu     sync.Mutex
+    leased []idgen.ID
+}
+
+// NewClient 以既有連線建立 Client
+func NewClient(cc grpc.ClientConnInterface) *Client {
+    return &Client{cc: cc}
+}
+
+// Next 取得一個 ID，啟用租用時優先從本地快取發放
+func (c *Client) Next(ctx context.Context) (idgen.ID, error) {
+    if c.LeaseSize <= 1 {
+        out := new(wrapperspb.BytesValue)
+        if err := c.cc.Invoke(ctx, nextMethod, &emptypb.Empty{}, out); err != nil {
+            return idgen.ID{}, err
+        }
+        var id idgen.ID
+        err := id.UnmarshalBinary(out.GetValue())
+        return id, err
+    }
+
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if len(c.leased) == 0 {
+        ids, err := c.NextN(ctx, c.LeaseSize)
+        if err != nil {
+            return idgen.ID{}, err
+        }
+        c.leased = ids
+    }
+    id := c.leased[0]
+    c.leased = c.leased[1:]
+    return id, nil
+}
+
+// NextN 直接向服務端請求 n 個遞增 ID，不經過本地快取
+func (c *Client) NextN(ctx context.Context, n int) ([]idgen.ID, error) {
+    if n <= 0 {
+        return nil, fmt.Errorf("idgengrpc: invalid batch size %d", n)
+    }
+    out := new(wrapperspb.BytesValue)
+    if err := c.cc.Invoke(ctx, nextBatchMethod, wrapperspb.UInt32(uint32(n)), out); err != nil {
+        return nil, err
+    }
+    buf := out.GetValue()
+    if len(buf) != n*idSize {
+        return nil, fmt.Errorf("%w: batch response length %d", idgen.ErrInvalidLength, len(buf))
+    }
+    ids := make([]idgen.ID, n)
+    for i := range ids {
+        copy(ids[i][:], buf[i*idSize:])
+    }
+    return ids, nil
+}
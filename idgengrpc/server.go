@@ -0,0 +1,149 @@
+// Package idgengrpc 提供 ID 發放的 gRPC 服務端與客戶端
+//
+// 服務定義見 idgen.proto；訊息只使用 protobuf well-known types，
+// 其他語言可直接由 proto 檔產生客戶端，Go 端則不需要額外的程式碼產生步驟
+package idgengrpc
+
+import (
+    "context"
+    "errors"
+
+    "github.com/pascal910107/idgen"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/emptypb"
+    "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// 服務與方法的完整名稱，需與 idgen.proto 一致
+const (
+    serviceName     = "idgen.v1.IDGenerator"
+    nextMethod      = "/" + serviceName + "/Next"
+    nextBatchMethod = "/" + serviceName + "/NextBatch"
+    idSize          = len(idgen.ID{})
+    defaultMaxBatch = 10000
+    batchChunk      = 1000 // NextBatch 每次向 Generator 取號的數量，之間檢查請求是否已取消
+)
+
+// IDGeneratorServer 為 IDGenerator 服務的介面，等同 protoc 產生的 server interface
+type IDGeneratorServer interface {
+    Next(context.Context, *emptypb.Empty) (*wrapperspb.BytesValue, error)
+    NextBatch(context.Context, *wrapperspb.UInt32Value) (*wrapperspb.BytesValue, error)
+}
+
+var _ IDGeneratorServer = (*Server)(nil)
+
+// Server 以單一 Generator 實作 IDGenerator 服務
+type Server struct {
+    gen *idgen.Generator
+
+    // MaxBatch 為 NextBatch 單次可請求的最大數量，0 代表 10000
+    MaxBatch int
+}
+
+// NewServer 建立以 g 發放 ID 的 Server
+func NewServer(g *idgen.Generator) *Server {
+    return &Server{gen: g}
+}
+
+// Register 將 srv 註冊到 gRPC server
+func Register(s grpc.ServiceRegistrar, srv IDGeneratorServer) {
+    s.RegisterService(&serviceDesc, srv)
+}
+
+// Next 實作 IDGenerator.Next
+func (s *Server) Next(ctx context.Context, _ *emptypb.Empty) (*wrapperspb.BytesValue, error) {
+    id, err := s.gen.NextContext(ctx)
+    if err != nil {
+        return nil, toStatus(err)
+    }
+    return wrapperspb.Bytes(id.Bytes()), nil
+}
+
+// NextBatch 實作 IDGenerator.NextBatch
+// 以每 batchChunk 個一段產生，請求取消或逾時時提前結束，不再為已放棄的請求繼續發號
+func (s *Server) NextBatch(ctx context.Context, req *wrapperspb.UInt32Value) (*wrapperspb.BytesValue, error) {
+    maxBatch := s.MaxBatch
+    if maxBatch <= 0 {
+        maxBatch = defaultMaxBatch
+    }
+    n := req.GetValue()
+    if n == 0 || n > uint32(maxBatch) {
+        return nil, status.Errorf(codes.InvalidArgument, "batch size %d not in 1-%d", n, maxBatch)
+    }
+
+    buf := make([]byte, 0, int(n)*idSize)
+    for left := int(n); left > 0; {
+        if err := ctx.Err(); err != nil {
+            return nil, toStatus(err)
+        }
+        ids, err := s.gen.NextN(min(left, batchChunk))
+        if err != nil {
+            return nil, toStatus(err)
+        }
+        for _, id := range ids {
+            buf = append(buf, id[:]...)
+        }
+        left -= len(ids)
+    }
+    return wrapperspb.Bytes(buf), nil
+}
+
+// toStatus 將產生器錯誤對應為 gRPC status：
+// 參數錯誤為 InvalidArgument，序列號用盡與時鐘回撥為 ResourceExhausted (稍後重試可能成功)，
+// context 錯誤為 Canceled 或 DeadlineExceeded，其餘 (含 Generator 已關閉) 為 Unavailable
+func toStatus(err error) error {
+    switch {
+    case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+        return status.FromContextError(err).Err()
+    case errors.Is(err, idgen.ErrInvalidArgument):
+        return status.Error(codes.InvalidArgument, err.Error())
+    case errors.Is(err, idgen.ErrSequenceExhausted) || errors.Is(err, idgen.ErrClockRollback):
+        return status.Error(codes.ResourceExhausted, err.Error())
+    default:
+        return status.Error(codes.Unavailable, err.Error())
+    }
+}
+
+// serviceDesc 等同 protoc-gen-go-grpc 由 idgen.proto 產生的描述
+var serviceDesc = grpc.ServiceDesc{
+    ServiceName: serviceName,
+    HandlerType: (*IDGeneratorServer)(nil),
+    Methods: []grpc.MethodDesc{
+        {MethodName: "Next", Handler: nextHandler},
+        {MethodName: "NextBatch", Handler: nextBatchHandler},
+    },
+    Streams:  []grpc.StreamDesc{},
+    Metadata: "idgen.proto",
+}
+
+func nextHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+    in := new(emptypb.Empty)
+    if err := dec(in); err != nil {
+        return nil, err
+    }
+    if interceptor == nil {
+        return srv.(IDGeneratorServer).Next(ctx, in)
+    }
+    info := &grpc.UnaryServerInfo{Server: srv, FullMethod: nextMethod}
+    handler := func(ctx context.Context, req any) (any, error) {
+        return srv.(IDGeneratorServer).Next(ctx, req.(*emptypb.Empty))
+    }
+    return interceptor(ctx, in, info, handler)
+}
+
+func nextBatchHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+    in := new(wrapperspb.UInt32Value)
+    if err := dec(in); err != nil {
+        return nil, err
+    }
+    if interceptor == nil {
+        return srv.(IDGeneratorServer).NextBatch(ctx, in)
+    }
+    info := &grpc.UnaryServerInfo{Server: srv, FullMethod: nextBatchMethod}
+    handler := func(ctx context.Context, req any) (any, error) {
+        return srv.(IDGeneratorServer).NextBatch(ctx, req.(*wrapperspb.UInt32Value))
+    }
+    return interceptor(ctx, in, info, handler)
+}
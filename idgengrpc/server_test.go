@@ -0,0 +1,65 @@
+package idgengrpc
+
+import (
+    "context"
+    "fmt"
+    "testing"
+
+    "github.com/pascal910107/idgen"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestToStatus(t *testing.T) {
+    tests := []struct {
+        err  error
+        want codes.Code
+    }{
+        {fmt.Errorf("%w: invalid id count -1", idgen.ErrInvalidArgument), codes.InvalidArgument},
+        {idgen.ErrGeneratorClosed, codes.Unavailable},
+        {fmt.Errorf("%w at 1", idgen.ErrSequenceExhausted), codes.ResourceExhausted},
+        {fmt.Errorf("%w by 5ms", idgen.ErrClockRollback), codes.ResourceExhausted},
+        {context.Canceled, codes.Canceled},
+        {context.DeadlineExceeded, codes.DeadlineExceeded},
+        {idgen.ErrClockBeforeEpoch, codes.Unavailable},
+    }
+    for _, tt := range tests {
+        t.Run(tt.err.Error(), func(t *testing.T) {
+            if got := status.Code(toStatus(tt.err)); got != tt.want {
+                t.Fatalf("toStatus(%v) = %v, want %v", tt.err, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestNextBatch(t *testing.T) {
+    g, err := idgen.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer g.Release()
+    s := NewServer(g)
+
+    resp, err := s.NextBatch(t.Context(), wrapperspb.UInt32(2500))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := len(resp.GetValue()); got != 2500*idSize {
+        t.Fatalf("NextBatch returned %d bytes, want %d", got, 2500*idSize)
+    }
+
+    ctx, cancel := context.WithCancel(t.Context())
+    cancel()
+    if _, err := s.NextBatch(ctx, wrapperspb.UInt32(5000)); status.Code(err) != codes.Canceled {
+        t.Fatalf("canceled NextBatch error = %v, want Canceled", err)
+    }
+    if _, err := s.NextBatch(t.Context(), wrapperspb.UInt32(0)); status.Code(err) != codes.InvalidArgument {
+        t.Fatalf("NextBatch(0) error = %v, want InvalidArgument", err)
+    }
+
+    g.Close()
+    if _, err := s.Next(t.Context(), nil); status.Code(err) != codes.Unavailable {
+        t.Fatalf("Next after Close error = %v, want Unavailable", err)
+    }
+}
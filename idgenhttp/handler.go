@@ -0,0 +1,135 @@
+// Package idgenhttp 提供 ID 產生與解碼的 HTTP 介面，可掛載在任意 mux 之下
+//
+//    mux.Handle("/ids/", http.StripPrefix("/ids", idgenhttp.NewHandler(g)))
+//
+// 端點 (相對於掛載點)：
+//
+//    GET /next                 產生單一 ID          → {"id": "..."}
+//    GET /next?n=10            產生多個遞增 ID      → {"ids": ["...", ...]}
+//    GET /decode/{id}          解碼 Parse 可辨識的 ID → {"id": "...", "epoch": 0, ...}
+//
+// /next 可加上 format=hex|uuid|base64url|base32 指定輸出格式，預設為 hex
+//
+// 另提供 RequestID 中介層，為每個請求產生 ID 並存入 context 與 X-Request-ID 標頭：
+//
+//    handler := idgenhttp.Middleware(g)(mux)
+//    id, _ := idgenhttp.FromContext(r.Context())
+package idgenhttp
+
+import (
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "strconv"
+    "time"
+
+    "github.com/pascal910107/idgen"
+)
+
+// defaultMaxBatch 為單次請求可產生的最大數量
+const defaultMaxBatch = 1000
+
+// Handler 以單一 Generator 處理 ID 產生與解碼請求
+type Handler struct {
+    gen *idgen.Generator
+    mux *http.ServeMux
+
+    // MaxBatch 為 /next?n= 允許的最大數量，0 代表 1000
+    MaxBatch int
+}
+
+// NewHandler 建立以 g 產生與解碼 ID 的 Handler
+func NewHandler(g *idgen.Generator) *Handler {
+    h := &Handler{gen: g, mux: http.NewServeMux()}
+    h.mux.HandleFunc("GET /next", h.next)
+    h.mux.HandleFunc("GET /decode/{id}", h.decode)
+    return h
+}
+
+// ServeHTTP 實作 http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) next(w http.ResponseWriter, r *http.Request) {
+    q := r.URL.Query()
+    format := idgen.FormatHex
+    if name := q.Get("format"); name != "" {
+        f, err := idgen.ParseFormat(name)
+        if err != nil || f == idgen.FormatBinary {
+            writeError(w, http.StatusBadRequest, fmt.Errorf("unsupported format %q", name))
+            return
+        }
+        format = f
+    }
+
+    if q.Get("n") == "" {
+        id, err := h.gen.NextContext(r.Context())
+        if err != nil {
+            writeError(w, http.StatusServiceUnavailable, err)
+            return
+        }
+        s, _ := id.Encode(format)
+        writeJSON(w, http.StatusOK, map[string]string{"id": s})
+        return
+    }
+
+    maxBatch := h.MaxBatch
+    if maxBatch <= 0 {
+        maxBatch = defaultMaxBatch
+    }
+    n, err := strconv.Atoi(q.Get("n"))
+    if err != nil || n < 1 || n > maxBatch {
+        writeError(w, http.StatusBadRequest, fmt.Errorf("n must be in 1-%d", maxBatch))
+        return
+    }
+    ids, err := h.gen.NextN(n)
+    if err != nil {
+        writeError(w, http.StatusServiceUnavailable, err)
+        return
+    }
+    out := make([]string, len(ids))
+    for i, id := range ids {
+        out[i], _ = id.Encode(format)
+    }
+    writeJSON(w, http.StatusOK, map[string][]string{"ids": out})
+}
+
+// decoded 為 /decode 的回應內容，各欄位依 Generator 的位元配置解碼
+type decoded struct {
+    ID              string    `json:"id"`
+    Epoch           uint16    `json:"epoch"`
+    TimestampMillis uint64    `json:"timestamp_ms"`
+    Time            time.Time `json:"time"`
+    Region          uint32    `json:"region"`
+    Node            uint32    `json:"node"`
+    Sequence        uint32    `json:"sequence"`
+}
+
+func (h *Handler) decode(w http.ResponseWriter, r *http.Request) {
+    id, err := idgen.Parse(r.PathValue("id"))
+    if err != nil {
+        writeError(w, http.StatusBadRequest, err)
+        return
+    }
+    d := h.gen.Decode(id)
+    writeJSON(w, http.StatusOK, decoded{
+        ID:              id.Hex(),
+        Epoch:           d.Epoch,
+        TimestampMillis: d.TimestampMillis,
+        Time:            h.gen.Time(id),
+        Region:          d.Region,
+        Node:            d.Node,
+        Sequence:        d.Sequence,
+    })
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(status)
+    json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+    writeJSON(w, status, map[string]string{"error": err.Error()})
+}
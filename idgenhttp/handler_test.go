@@ -0,0 +1,43 @@
+package idgenhttp_test
+
+import (
+    "encoding/json"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/pascal910107/idgen"
+    "github.com/pascal910107/idgen/idgenhttp"
+)
+
+func TestDecodeUsesGeneratorLayout(t *testing.T) {
+    layout := idgen.Layout{EpochBits: 16, TimestampBits: 64, RegionBits: 8, NodeBits: 24, SequenceBits: 16}
+    g, err := idgen.New(idgen.WithLayout(layout), idgen.WithRegionNode(5, 1<<20))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer g.Release()
+    id, err := g.Next()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    rec := httptest.NewRecorder()
+    idgenhttp.NewHandler(g).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/decode/"+id.Hex(), nil))
+    if rec.Code != http.StatusOK {
+        t.Fatalf("status %d: %s", rec.Code, rec.Body)
+    }
+    var got struct {
+        TimestampMillis uint64 `json:"timestamp_ms"`
+        Region          uint32 `json:"region"`
+        Node            uint32 `json:"node"`
+        Sequence        uint32 `json:"sequence"`
+    }
+    if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+        t.Fatal(err)
+    }
+    want := g.Decode(id)
+    if got.Region != 5 || got.Node != 1<<20 || got.Sequence != want.Sequence || got.TimestampMillis != want.TimestampMillis {
+        t.Fatalf("decode = %+v, want region 5 node %d sequence %d ts %d", got, 1<<20, want.Sequence, want.TimestampMillis)
+    }
+}
@@ -0,0 +1,76 @@
+package idgenhttp
+
+import (
+    "context"
+    "net/http"
+
+    "github.com/pascal910107/idgen"
+)
+
+// RequestIDHeader 為預設用來傳遞請求 ID 的標頭
+const RequestIDHeader = "X-Request-ID"
+
+// Source 為可產生 ID 的來源，*idgen.Generator、*idgen.IDPool 與 *idgen.ShardedGenerator 皆滿足
+type Source interface {
+    Next() (idgen.ID, error)
+}
+
+// ctxKey 為存放請求 ID 的 context key
+type ctxKey struct{}
+
+// NewContext 回傳帶有請求 ID 的 ctx
+func NewContext(ctx context.Context, id idgen.ID) context.Context {
+    return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext 取出 RequestID 中介層存入的請求 ID
+func FromContext(ctx context.Context) (idgen.ID, bool) {
+    id, ok := ctx.Value(ctxKey{}).(idgen.ID)
+    return id, ok
+}
+
+// RequestID 為每個請求產生 ID 的中介層：存入 request context 並設定於回應標頭
+//
+//    http.ListenAndServe(":8080", idgenhttp.RequestID{Source: g}.Wrap(mux))
+type RequestID struct {
+    Source Source
+
+    // Header 為讀取與回寫請求 ID 的標頭，空字串代表 X-Request-ID
+    Header string
+
+    // TrustIncoming 為 true 時，請求已帶有可被 idgen.Parse 解析的標頭則沿用而不重新產生
+    // 僅應在上游 (例如內部閘道) 可信時開啟
+    TrustIncoming bool
+}
+
+// Middleware 回傳以 s 產生請求 ID 的中介層，等同 RequestID{Source: s}.Wrap
+func Middleware(s Source) func(http.Handler) http.Handler {
+    return RequestID{Source: s}.Wrap
+}
+
+// Wrap 以中介層包裝 next；產生 ID 失敗時回應 500 且不呼叫 next
+func (m RequestID) Wrap(next http.Handler) http.Handler {
+    header := m.Header
+    if header == "" {
+        header = RequestIDHeader
+    }
+    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        id, ok := idgen.ID{}, false
+        if m.TrustIncoming {
+            if v := r.Header.Get(header); v != "" {
+                parsed, err := idgen.Parse(v)
+                id, ok = parsed, err == nil
+            }
+        }
+        if !ok {
+            var err error
+            if id, err = m.Source.Next(); err != nil {
+                http.Error(w, "request id: "+err.Error(), http.StatusInternalServerError)
+                return
+            }
+        }
+
+        w.Header().Set(header, id.Hex())
+        next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), id)))
+    })
+}
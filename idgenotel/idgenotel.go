@@ -0,0 +1,69 @@
+// Package idgenotel 讓 idgen.ID 作為 OpenTelemetry 的 trace ID 使用
+//
+// ID 與 trace.TraceID 同為 16 bytes，可直接互轉；IDGenerator 讓 OTel SDK 以 Generator 產生 trace ID，
+// 使 trace ID 依時間排序並可解碼出產生的 region 與 node：
+//
+//    tp := sdktrace.NewTracerProvider(sdktrace.WithIDGenerator(idgenotel.NewIDGenerator(g)))
+//
+// 注意：TraceIDRatioBased 取樣器依 trace ID 低 8 bytes 判斷，idgen.ID 的低位並不隨機，
+// 使用比例取樣時建議搭配 idgen.WithEntropyBits 以免取樣偏差
+package idgenotel
+
+import (
+    "context"
+    "crypto/rand"
+
+    "github.com/pascal910107/idgen"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/trace"
+)
+
+// TraceID 將 id 轉為 trace.TraceID
+func TraceID(id idgen.ID) trace.TraceID {
+    return trace.TraceID(id)
+}
+
+// FromTraceID 將 trace.TraceID 轉回 idgen.ID；非由 IDGenerator 產生的 trace ID 解碼結果沒有意義
+func FromTraceID(tid trace.TraceID) idgen.ID {
+    return idgen.ID(tid)
+}
+
+// Source 為可產生 ID 的來源，*idgen.Generator、*idgen.IDPool 與 *idgen.ShardedGenerator 皆滿足
+type Source interface {
+    Next() (idgen.ID, error)
+}
+
+// IDGenerator 實作 sdktrace.IDGenerator：trace ID 來自 Source，span ID 為隨機值
+type IDGenerator struct {
+    src Source
+}
+
+var _ sdktrace.IDGenerator = (*IDGenerator)(nil)
+
+// NewIDGenerator 建立以 src 產生 trace ID 的 IDGenerator
+func NewIDGenerator(src Source) *IDGenerator {
+    return &IDGenerator{src: src}
+}
+
+// NewIDs 實作 sdktrace.IDGenerator
+// Source 產生失敗時 (例如時鐘回撥且策略為回報錯誤) 改用隨機 trace ID，避免追蹤中斷
+func (g *IDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+    var tid trace.TraceID
+    if id, err := g.src.Next(); err == nil {
+        tid = trace.TraceID(id)
+    } else {
+        for !tid.IsValid() {
+            rand.Read(tid[:])
+        }
+    }
+    return tid, g.NewSpanID(ctx, tid)
+}
+
+// NewSpanID 實作 sdktrace.IDGenerator，回傳非零的隨機 span ID
+func (g *IDGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+    var sid trace.SpanID
+    for !sid.IsValid() {
+        rand.Read(sid[:])
+    }
+    return sid
+}
@@ -0,0 +1,176 @@
+// Package idgenpgx 讓 pgx v5 直接以 idgen.ID 讀寫 Postgres uuid 與 bytea 欄位 (含陣列)
+// 不經過 database/sql 的 Valuer / Scanner 與字串轉換
+//
+// 每條連線建立後呼叫 Register 註冊至其型別表，例如 pgxpool：
+//
+//    cfg.AfterConnect = func(ctx context.Context, conn *pgx.Conn) error {
+//        idgenpgx.Register(conn.TypeMap())
+//        return nil
+//    }
+//
+// 註冊後可直接傳入 idgen.ID 或 []idgen.ID 作為參數，並掃描至 *idgen.ID 或 *[]idgen.ID：
+//
+//    rows, err := conn.Query(ctx, "SELECT id FROM orders WHERE id = ANY($1)", ids)
+package idgenpgx
+
+import (
+    "database/sql/driver"
+    "encoding/hex"
+    "fmt"
+
+    "github.com/jackc/pgx/v5/pgtype"
+    "github.com/pascal910107/idgen"
+)
+
+// Register 以支援 idgen.ID 的 codec 取代 m 中的 uuid、bytea 及其陣列型別
+// 其他 Go 型別仍交由原本的 codec 處理；參數 OID 未知時 (例如 simple protocol) idgen.ID 預設對應 uuid
+func Register(m *pgtype.Map) {
+    uuid := register(m, "uuid", pgtype.UUIDOID, uuidFormat)
+    bytea := register(m, "bytea", pgtype.ByteaOID, byteaFormat)
+    m.RegisterType(&pgtype.Type{Name: "_uuid", OID: pgtype.UUIDArrayOID, Codec: &pgtype.ArrayCodec{ElementType: uuid}})
+    m.RegisterType(&pgtype.Type{Name: "_bytea", OID: pgtype.ByteaArrayOID, Codec: &pgtype.ArrayCodec{ElementType: bytea}})
+
+    m.RegisterDefaultPgType(idgen.ID{}, "uuid")
+    m.RegisterDefaultPgType([]idgen.ID{}, "_uuid")
+}
+
+func register(m *pgtype.Map, name string, oid uint32, kind columnKind) *pgtype.Type {
+    var next pgtype.Codec
+    if t, ok := m.TypeForName(name); ok {
+        next = t.Codec
+    } else if kind == uuidFormat {
+        next = pgtype.UUIDCodec{}
+    } else {
+        next = pgtype.ByteaCodec{}
+    }
+    t := &pgtype.Type{Name: name, OID: oid, Codec: &codec{next: next, kind: kind}}
+    m.RegisterType(t)
+    return t
+}
+
+// ------------- codec ------------- //
+
+// columnKind 區分 uuid 與 bytea 的文字格式
+type columnKind uint8
+
+const (
+    uuidFormat columnKind = iota
+    byteaFormat
+)
+
+// codec 處理 idgen.ID 與 *idgen.ID，其餘型別轉交 next
+type codec struct {
+    next pgtype.Codec
+    kind columnKind
+}
+
+func (c *codec) FormatSupported(format int16) bool { return c.next.FormatSupported(format) }
+
+func (c *codec) PreferredFormat() int16 { return c.next.PreferredFormat() }
+
+func (c *codec) PlanEncode(m *pgtype.Map, oid uint32, format int16, value any) pgtype.EncodePlan {
+    if _, ok := value.(idgen.ID); ok {
+        switch format {
+        case pgtype.BinaryFormatCode:
+            return encodeBinary{}
+        case pgtype.TextFormatCode:
+            return encodeText{kind: c.kind}
+        }
+    }
+    return c.next.PlanEncode(m, oid, format, value)
+}
+
+func (c *codec) PlanScan(m *pgtype.Map, oid uint32, format int16, target any) pgtype.ScanPlan {
+    if _, ok := target.(*idgen.ID); ok {
+        switch format {
+        case pgtype.BinaryFormatCode:
+            return scanBinary{}
+        case pgtype.TextFormatCode:
+            return scanText{kind: c.kind}
+        }
+    }
+    return c.next.PlanScan(m, oid, format, target)
+}
+
+func (c *codec) DecodeDatabaseSQLValue(m *pgtype.Map, oid uint32, format int16, src []byte) (driver.Value, error) {
+    return c.next.DecodeDatabaseSQLValue(m, oid, format, src)
+}
+
+func (c *codec) DecodeValue(m *pgtype.Map, oid uint32, format int16, src []byte) (any, error) {
+    return c.next.DecodeValue(m, oid, format, src)
+}
+
+// ------------- 編碼 ------------- //
+
+// isNull 與 idgen.ID.Value 一致：開啟 SetEmptyAsNil 時 NilID 寫入為 NULL
+func isNull(id idgen.ID) bool {
+    return id.IsNil() && idgen.EmptyAsNil()
+}
+
+type encodeBinary struct{}
+
+func (encodeBinary) Encode(value any, buf []byte) ([]byte, error) {
+    id := value.(idgen.ID)
+    if isNull(id) {
+        return nil, nil
+    }
+    return append(buf, id[:]...), nil
+}
+
+type encodeText struct{ kind columnKind }
+
+func (p encodeText) Encode(value any, buf []byte) ([]byte, error) {
+    id := value.(idgen.ID)
+    if isNull(id) {
+        return nil, nil
+    }
+    if p.kind == byteaFormat {
+        return hex.AppendEncode(append(buf, `\x`...), id[:]), nil
+    }
+    return append(buf, id.UUIDString()...), nil
+}
+
+// ------------- 掃描 ------------- //
+
+// NULL 與 idgen.ID.Scan 相同，得到零值 ID
+
+type scanBinary struct{}
+
+func (scanBinary) Scan(src []byte, target any) error {
+    dst := target.(*idgen.ID)
+    if src == nil {
+        *dst = idgen.ID{}
+        return nil
+    }
+    if len(src) != len(dst) {
+        return fmt.Errorf("%w: got %d bytes, want %d", idgen.ErrInvalidLength, len(src), len(dst))
+    }
+    copy(dst[:], src)
+    return nil
+}
+
+type scanText struct{ kind columnKind }
+
+func (p scanText) Scan(src []byte, target any) error {
+    dst := target.(*idgen.ID)
+    if src == nil {
+        *dst = idgen.ID{}
+        return nil
+    }
+    s := string(src)
+    if p.kind == byteaFormat {
+        if len(s) < 2 || s[:2] != `\x` {
+            return fmt.Errorf("%w: bytea text %q is not hex", idgen.ErrInvalidEncoding, s)
+        }
+        s = s[2:]
+        if len(s) != 32 {
+            return fmt.Errorf("%w: got %d hex digits, want 32", idgen.ErrInvalidLength, len(s))
+        }
+    }
+    id, err := idgen.Parse(s)
+    if err != nil {
+        return err
+    }
+    *dst = id
+    return nil
+}
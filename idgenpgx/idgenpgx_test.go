@@ -0,0 +1,99 @@
+package idgenpgx_test
+
+import (
+    "bytes"
+    "testing"
+
+    "github.com/jackc/pgx/v5/pgtype"
+    "github.com/pascal910107/idgen"
+    "github.com/pascal910107/idgen/idgenpgx"
+)
+
+func newMap() *pgtype.Map {
+    m := pgtype.NewMap()
+    idgenpgx.Register(m)
+    return m
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+    m := newMap()
+    id := idgen.FromParts(1, 123456789, 2, 3, 4)
+
+    tests := []struct {
+        name   string
+        oid    uint32
+        format int16
+        want   string // 文字格式的預期編碼
+    }{
+        {"uuid binary", pgtype.UUIDOID, pgtype.BinaryFormatCode, ""},
+        {"uuid text", pgtype.UUIDOID, pgtype.TextFormatCode, id.UUIDString()},
+        {"bytea binary", pgtype.ByteaOID, pgtype.BinaryFormatCode, ""},
+        {"bytea text", pgtype.ByteaOID, pgtype.TextFormatCode, `\x` + id.Hex()},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            buf, err := m.Encode(tt.oid, tt.format, id, nil)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if tt.format == pgtype.BinaryFormatCode && !bytes.Equal(buf, id[:]) {
+                t.Fatalf("Encode = %x, want %x", buf, id[:])
+            }
+            if tt.want != "" && string(buf) != tt.want {
+                t.Fatalf("Encode = %q, want %q", buf, tt.want)
+            }
+            var got idgen.ID
+            if err := m.Scan(tt.oid, tt.format, buf, &got); err != nil {
+                t.Fatal(err)
+            }
+            if got != id {
+                t.Fatalf("Scan = %s, want %s", got, id)
+            }
+        })
+    }
+}
+
+func TestCodecArray(t *testing.T) {
+    m := newMap()
+    ids := []idgen.ID{idgen.FromParts(0, 1, 1, 2, 0), idgen.FromParts(0, 2, 1, 2, 0)}
+    for _, format := range []int16{pgtype.BinaryFormatCode, pgtype.TextFormatCode} {
+        buf, err := m.Encode(pgtype.UUIDArrayOID, format, ids, nil)
+        if err != nil {
+            t.Fatal(err)
+        }
+        var got []idgen.ID
+        if err := m.Scan(pgtype.UUIDArrayOID, format, buf, &got); err != nil {
+            t.Fatal(err)
+        }
+        if len(got) != 2 || got[0] != ids[0] || got[1] != ids[1] {
+            t.Fatalf("format %d: Scan = %v, want %v", format, got, ids)
+        }
+    }
+}
+
+func TestCodecNull(t *testing.T) {
+    m := newMap()
+    got := idgen.FromParts(0, 1, 1, 2, 0)
+    if err := m.Scan(pgtype.UUIDOID, pgtype.BinaryFormatCode, nil, &got); err != nil || !got.IsNil() {
+        t.Fatalf("Scan(NULL) = %s, %v, want NilID", got, err)
+    }
+
+    defer idgen.SetEmptyAsNil(idgen.EmptyAsNil())
+    idgen.SetEmptyAsNil(true)
+    if buf, err := m.Encode(pgtype.UUIDOID, pgtype.BinaryFormatCode, idgen.NilID, nil); err != nil || buf != nil {
+        t.Fatalf("Encode(NilID) = %x, %v, want NULL", buf, err)
+    }
+}
+
+func TestCodecKeepsOtherTypes(t *testing.T) {
+    m := newMap()
+    raw := []byte{1, 2, 3}
+    buf, err := m.Encode(pgtype.ByteaOID, pgtype.BinaryFormatCode, raw, nil)
+    if err != nil {
+        t.Fatal(err)
+    }
+    var got []byte
+    if err := m.Scan(pgtype.ByteaOID, pgtype.BinaryFormatCode, buf, &got); err != nil || !bytes.Equal(got, raw) {
+        t.Fatalf("Scan = %x, %v, want %x", got, err, raw)
+    }
+}
@@ -0,0 +1,51 @@
+// Package idgenzap 提供記錄 idgen.ID 的 zap 欄位建構函式
+//
+//    logger.Info("order created", idgenzap.ID("order_id", id))
+//    logger.Debug("id issued", idgenzap.Decoded("id", id))
+package idgenzap
+
+import (
+    "github.com/pascal910107/idgen"
+    "go.uber.org/zap"
+    "go.uber.org/zap/zapcore"
+)
+
+// ID 回傳以 hex 字串記錄 id 的欄位
+// 直接建立字串欄位，不經過 fmt.Stringer 與反射
+func ID(key string, id idgen.ID) zap.Field {
+    return zap.String(key, id.Hex())
+}
+
+// IDs 回傳以 hex 字串陣列記錄 ids 的欄位
+func IDs(key string, ids []idgen.ID) zap.Field {
+    return zap.Array(key, idArray(ids))
+}
+
+// Decoded 回傳記錄 id 及其解碼欄位 (time、epoch、region、node、sequence) 的物件欄位
+func Decoded(key string, id idgen.ID) zap.Field {
+    return zap.Object(key, decoded(id))
+}
+
+type idArray []idgen.ID
+
+func (a idArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+    var buf [32]byte
+    for _, id := range a {
+        enc.AppendByteString(id.AppendHex(buf[:0]))
+    }
+    return nil
+}
+
+type decoded idgen.ID
+
+func (d decoded) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+    id := idgen.ID(d)
+    var buf [32]byte
+    enc.AddByteString("id", id.AppendHex(buf[:0]))
+    enc.AddTime("time", id.Time())
+    enc.AddUint16("epoch", id.Epoch())
+    enc.AddUint16("region", id.Region())
+    enc.AddUint16("node", id.Node())
+    enc.AddUint16("sequence", id.Sequence())
+    return nil
+}
@@ -0,0 +1,37 @@
+// Package idgenzerolog 提供以 zerolog 記錄 idgen.ID 的輔助函式
+//
+//    idgenzerolog.ID(log.Info(), "order_id", id).Msg("order created")
+//    log.Debug().Object("id", idgenzerolog.Decoded(id)).Msg("id issued")
+package idgenzerolog
+
+import (
+    "github.com/pascal910107/idgen"
+    "github.com/rs/zerolog"
+)
+
+// ID 以 hex 字串將 id 寫入 e 並回傳 e，直接編碼原始 bytes 而不配置字串
+func ID(e *zerolog.Event, key string, id idgen.ID) *zerolog.Event {
+    return e.Hex(key, id[:])
+}
+
+// Ctx 同 ID，用於 zerolog.Context (例如 logger.With())
+func Ctx(c zerolog.Context, key string, id idgen.ID) zerolog.Context {
+    return c.Hex(key, id[:])
+}
+
+// Decoded 回傳記錄 id 及其解碼欄位 (time、epoch、region、node、sequence) 的 zerolog.LogObjectMarshaler
+func Decoded(id idgen.ID) zerolog.LogObjectMarshaler {
+    return decoded(id)
+}
+
+type decoded idgen.ID
+
+func (d decoded) MarshalZerologObject(e *zerolog.Event) {
+    id := idgen.ID(d)
+    e.Hex("id", id[:]).
+        Time("time", id.Time()).
+        Uint16("epoch", id.Epoch()).
+        Uint16("region", id.Region()).
+        Uint16("node", id.Node()).
+        Uint16("sequence", id.Sequence())
+}
@@ -0,0 +1,89 @@
+package idgen
+
+import (
+    "fmt"
+    "math/big"
+    "time"
+)
+
+// ------------- ID 範圍 ------------- //
+
+// IDRange 為 [From, To] 的 ID 閉區間，依 ID.Compare 的順序
+// 用於分片指派、回填等以 ID 鍵範圍切分工作的場景；From 大於 To 時為空範圍
+type IDRange struct {
+    From ID
+    To   ID
+}
+
+// TimeRange 回傳涵蓋 [start, end) 時間內產生之 ID 的範圍，以 CustomEpoch 為起算點
+// 與 MinIDAt / MaxIDAt 相同，只涵蓋 epoch 0 的 ID；end 不晚於 start 時回傳空範圍
+func TimeRange(start, end time.Time) IDRange {
+    if !end.After(start) {
+        return IDRange{From: MaxID, To: MinID}
+    }
+    return IDRange{From: MinIDAt(start), To: MaxIDAt(end.Add(-time.Millisecond))}
+}
+
+// FullRange 回傳涵蓋所有 ID 的範圍
+func FullRange() IDRange {
+    return IDRange{From: MinID, To: MaxID}
+}
+
+// IsEmpty 回傳範圍是否不含任何 ID
+func (r IDRange) IsEmpty() bool {
+    return r.From.Compare(r.To) > 0
+}
+
+// Contains 回傳 id 是否位於範圍內
+func (r IDRange) Contains(id ID) bool {
+    return r.From.Compare(id) <= 0 && id.Compare(r.To) <= 0
+}
+
+// Overlaps 回傳兩個範圍是否有交集
+func (r IDRange) Overlaps(other IDRange) bool {
+    if r.IsEmpty() || other.IsEmpty() {
+        return false
+    }
+    return r.From.Compare(other.To) <= 0 && other.From.Compare(r.To) <= 0
+}
+
+// Split 將範圍依數值平均切分為最多 n 個連續且不重疊的子範圍，聯集等於原範圍
+// 範圍內的 ID 數少於 n 時，每個 ID 自成一個子範圍；空範圍回傳 nil。n <= 0 時 panic
+//
+// 切分依 128 位元數值而非實際 ID 分布，時間範圍內各子範圍大致對應等長的時間區間
+func (r IDRange) Split(n int) []IDRange {
+    if n <= 0 {
+        panic("idgen: Split called with non-positive n")
+    }
+    if r.IsEmpty() {
+        return nil
+    }
+
+    from := new(big.Int).SetBytes(r.From[:])
+    size := new(big.Int).SetBytes(r.To[:])
+    size.Sub(size, from).Add(size, big.NewInt(1))
+    if size.IsInt64() && size.Int64() < int64(n) {
+        n = int(size.Int64())
+    }
+
+    parts := make([]IDRange, n)
+    bn := big.NewInt(int64(n))
+    start := r.From
+    for i := 1; i <= n; i++ {
+        // 第 i 段終點為 from + size*i/n - 1
+        end := new(big.Int).Mul(size, big.NewInt(int64(i)))
+        end.Div(end, bn).Add(end, from).Sub(end, big.NewInt(1))
+        var to ID
+        end.FillBytes(to[:])
+        parts[i-1] = IDRange{From: start, To: to}
+        if i < n {
+            end.Add(end, big.NewInt(1)).FillBytes(start[:])
+        }
+    }
+    return parts
+}
+
+// String 回傳 [from, to] 形式的 hex 表示
+func (r IDRange) String() string {
+    return fmt.Sprintf("[%s, %s]", r.From.Hex(), r.To.Hex())
+}
@@ -0,0 +1,67 @@
+package idgen
+
+import (
+    "context"
+    "iter"
+)
+
+// ------------- 迭代器 ------------- //
+
+// All 回傳不斷產生新 ID 的迭代器，可直接以 range 使用，跳出迴圈即停止
+// 產生失敗 (例如已 Close、SequenceFail 用盡或時鐘回撥回報錯誤) 時迭代結束；需要得知原因時改用 AllContext
+//
+//    for id := range g.All() {
+//        if done(id) {
+//            break
+//        }
+//    }
+func (g *Generator) All() iter.Seq[ID] {
+    return func(yield func(ID) bool) {
+        for {
+            id, err := g.Next()
+            if err != nil || !yield(id) {
+                return
+            }
+        }
+    }
+}
+
+// Take 回傳最多產生 n 個 ID 的迭代器，產生失敗時提前結束 (同 All)
+//
+//    for id := range g.Take(100) {
+//        rows = append(rows, Row{ID: id})
+//    }
+func (g *Generator) Take(n int) iter.Seq[ID] {
+    return func(yield func(ID) bool) {
+        for range n {
+            id, err := g.Next()
+            if err != nil || !yield(id) {
+                return
+            }
+        }
+    }
+}
+
+// AllContext 回傳以 NextContext 產生 ID 的迭代器，等待可被 ctx 取消
+// 產生失敗時以零值 ID 與錯誤作為最後一個元素後結束
+//
+//    for id, err := range g.AllContext(ctx) {
+//        if err != nil {
+//            return err
+//        }
+//        ...
+//    }
+func (g *Generator) AllContext(ctx context.Context) iter.Seq2[ID, error] {
+    return func(yield func(ID, error) bool) {
+        for {
+            id, err := g.NextContext(ctx)
+            if err != nil {
+                yield(ID{}, err)
+                return
+            }
+            if !yield(id, nil) {
+                return
+            }
+        }
+    }
+}
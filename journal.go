@@ -0,0 +1,262 @@
+package idgen
+
+import (
+    "bufio"
+    "encoding/binary"
+    "fmt"
+    "hash/crc32"
+    "io"
+    "os"
+    "sync"
+)
+
+// ------------- 發號日誌 ------------- //
+
+// journalMagic 為日誌的檔頭 (含格式版本)
+var journalMagic = [4]byte{'I', 'D', 'J', 1}
+
+// journalMaxRecord 為單筆記錄最多包含的 ID 數量，限制讀取端需要配置的緩衝
+const journalMaxRecord = 4096
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// JournalWriter 以只附加的二進位格式記錄發出的 ID，供需要完整稽核軌跡的部署使用
+//
+// 檔頭之後每筆記錄為 4 bytes 長度 (Big‑Endian) | 長度為 16 倍數的 ID 內容 | 4 bytes CRC‑32C；
+// 寫入中途當機只會留下不完整的最後一筆，JournalReader 會回報 io.ErrUnexpectedEOF，之前的記錄仍可讀取；
+// OpenJournal 重新開啟時會先截去該筆，之後寫入的記錄才能被讀取
+// 並發安全，可同時被多個 Generator 共用
+type JournalWriter struct {
+    // SyncWrites 為 true 時每筆記錄寫入後呼叫底層的 Sync (例如 *os.File)，確保回傳前已落盤
+    SyncWrites bool
+
+    mu      sync.Mutex
+    w       io.Writer
+    started bool
+    buf     []byte
+}
+
+// NewJournalWriter 建立寫入 w 的 JournalWriter，第一次寫入時輸出檔頭
+func NewJournalWriter(w io.Writer) *JournalWriter {
+    return &JournalWriter{w: w}
+}
+
+// OpenJournal 以附加模式開啟 (必要時建立) path 的日誌檔，預設 SyncWrites 為 true
+// 檔案已有內容時會檢查檔頭並掃描所有記錄；上次寫入中途當機留下的不完整殘尾會先被截去，
+// 之後的記錄接在最後一筆完整的記錄之後。殘尾之前的記錄損毀時回傳 ErrInvalidEncoding，不修改檔案
+func OpenJournal(path string) (*JournalWriter, error) {
+    f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+    if err != nil {
+        return nil, err
+    }
+    fi, err := f.Stat()
+    if err != nil {
+        f.Close()
+        return nil, err
+    }
+    valid, err := recoverJournal(f, fi.Size())
+    if err != nil {
+        f.Close()
+        return nil, fmt.Errorf("%s: %w", path, err)
+    }
+    if valid < fi.Size() {
+        if err := f.Truncate(valid); err != nil {
+            f.Close()
+            return nil, err
+        }
+        if err := f.Sync(); err != nil {
+            f.Close()
+            return nil, err
+        }
+    }
+    return &JournalWriter{SyncWrites: true, w: f, started: valid > 0}, nil
+}
+
+// recoverJournal 掃描大小為 size 的日誌 f，回傳最後一筆完整且 CRC 正確的記錄結尾位置
+// 其後只剩不完整的記錄、延伸到檔尾的 CRC 錯誤記錄或全為零的內容時視為寫入中途當機的殘尾；
+// 殘尾之後仍有其他內容時代表檔案損毀而非當機，回傳 ErrInvalidEncoding
+func recoverJournal(f *os.File, size int64) (int64, error) {
+    r := bufio.NewReader(io.NewSectionReader(f, 0, size))
+    var magic [4]byte
+    if n, _ := io.ReadFull(r, magic[:]); n < len(magic) || magic != journalMagic {
+        if string(magic[:n]) == string(journalMagic[:n]) && int64(n) == size {
+            return 0, nil // 空檔案或檔頭寫到一半
+        }
+        return 0, fmt.Errorf("%w: not an id journal", ErrInvalidEncoding)
+    }
+
+    off := int64(len(magic))
+    var hdr [4]byte
+    var buf []byte
+    for off < size {
+        if size-off < int64(len(hdr))+4 {
+            return off, nil // 連長度與 CRC 都不完整
+        }
+        if _, err := io.ReadFull(r, hdr[:]); err != nil {
+            return 0, err
+        }
+        n := binary.BigEndian.Uint32(hdr[:])
+        end := off + int64(len(hdr)) + int64(n) + 4
+        if n == 0 || n%16 != 0 || n > 16*journalMaxRecord {
+            return off, journalTail(f, off, size)
+        }
+        if end > size {
+            return off, nil // 記錄內容不完整
+        }
+        buf = append(buf[:0], make([]byte, n+4)...)
+        if _, err := io.ReadFull(r, buf); err != nil {
+            return 0, err
+        }
+        if crc32.Checksum(buf[:n], crc32c) != binary.BigEndian.Uint32(buf[n:]) {
+            if end == size {
+                return off, nil // 最後一筆的內容尚未完整落盤
+            }
+            return off, journalTail(f, off, size)
+        }
+        off = end
+    }
+    return off, nil
+}
+
+// journalTail 確認 f 自 off 起到 size 皆為零 (延伸檔案後內容尚未落盤)，否則回傳 ErrInvalidEncoding
+func journalTail(f *os.File, off, size int64) error {
+    r := bufio.NewReader(io.NewSectionReader(f, off, size-off))
+    for {
+        c, err := r.ReadByte()
+        if err == io.EOF {
+            return nil
+        }
+        if err != nil {
+            return err
+        }
+        if c != 0 {
+            return fmt.Errorf("%w: corrupt journal record at offset %d", ErrInvalidEncoding, off)
+        }
+    }
+}
+
+// Write 將 ids 寫為一筆記錄 (超過 4096 個時分成多筆)，SyncWrites 時於落盤後才回傳
+func (j *JournalWriter) Write(ids ...ID) error {
+    j.mu.Lock()
+    defer j.mu.Unlock()
+
+    for len(ids) > 0 {
+        n := min(len(ids), journalMaxRecord)
+        if err := j.writeRecord(ids[:n]); err != nil {
+            return err
+        }
+        ids = ids[n:]
+    }
+    if s, ok := j.w.(interface{ Sync() error }); ok && j.SyncWrites {
+        return s.Sync()
+    }
+    return nil
+}
+
+func (j *JournalWriter) writeRecord(ids []ID) error {
+    b := j.buf[:0]
+    if !j.started {
+        b = append(b, journalMagic[:]...)
+    }
+    b = binary.BigEndian.AppendUint32(b, uint32(16*len(ids)))
+    start := len(b)
+    for _, id := range ids {
+        b = append(b, id[:]...)
+    }
+    b = binary.BigEndian.AppendUint32(b, crc32.Checksum(b[start:], crc32c))
+    j.buf = b
+
+    if _, err := j.w.Write(b); err != nil {
+        return err
+    }
+    j.started = true
+    return nil
+}
+
+// Close 關閉底層的 io.Writer (若實作 io.Closer)
+func (j *JournalWriter) Close() error {
+    j.mu.Lock()
+    defer j.mu.Unlock()
+    if c, ok := j.w.(io.Closer); ok {
+        return c.Close()
+    }
+    return nil
+}
+
+// JournalReader 依序讀取 JournalWriter 寫入的 ID
+type JournalReader struct {
+    r       *bufio.Reader
+    started bool
+    record  []byte // 目前記錄中尚未回傳的 ID
+}
+
+// NewJournalReader 建立自 r 讀取的 JournalReader
+func NewJournalReader(r io.Reader) *JournalReader {
+    return &JournalReader{r: bufio.NewReader(r)}
+}
+
+// Read 回傳下一個 ID，日誌結束時回傳 io.EOF
+// 最後一筆記錄不完整 (寫入中途當機) 時回傳 io.ErrUnexpectedEOF，長度或 CRC 不符時回傳 ErrInvalidEncoding
+func (jr *JournalReader) Read() (ID, error) {
+    if len(jr.record) == 0 {
+        if err := jr.next(); err != nil {
+            return ID{}, err
+        }
+    }
+    var id ID
+    copy(id[:], jr.record)
+    jr.record = jr.record[len(id):]
+    return id, nil
+}
+
+// next 讀取並驗證下一筆記錄
+func (jr *JournalReader) next() error {
+    if !jr.started {
+        var magic [4]byte
+        if _, err := io.ReadFull(jr.r, magic[:]); err != nil {
+            return err
+        }
+        if magic != journalMagic {
+            return fmt.Errorf("%w: not an id journal", ErrInvalidEncoding)
+        }
+        jr.started = true
+    }
+
+    var hdr [4]byte
+    if _, err := io.ReadFull(jr.r, hdr[:]); err != nil {
+        return err // 記錄之間結束為 io.EOF，長度欄位截斷為 io.ErrUnexpectedEOF
+    }
+    n := binary.BigEndian.Uint32(hdr[:])
+    if n == 0 || n%16 != 0 || n > 16*journalMaxRecord {
+        return fmt.Errorf("%w: journal record length %d", ErrInvalidEncoding, n)
+    }
+    buf := make([]byte, n+4)
+    if _, err := io.ReadFull(jr.r, buf); err != nil {
+        return io.ErrUnexpectedEOF
+    }
+    if crc32.Checksum(buf[:n], crc32c) != binary.BigEndian.Uint32(buf[n:]) {
+        return fmt.Errorf("%w: journal record checksum mismatch", ErrInvalidEncoding)
+    }
+    jr.record = buf[:n]
+    return nil
+}
+
+// WithJournal 將此 Generator 發出的每個 ID 寫入 j，寫入失敗時該次呼叫回傳錯誤且不回傳 ID
+// 寫入於持有內部鎖時同步進行，SyncWrites 會明顯降低吞吐量；ReserveBlock 會記錄區塊內的所有 ID
+func WithJournal(j *JournalWriter) Option {
+    return func(g *Generator) error {
+        g.journal = j
+        return nil
+    }
+}
+
+// logIssued 將 ids 寫入日誌 (若有設定)，呼叫端須持有 g.mu
+func (g *Generator) logIssued(ids ...ID) error {
+    if g.journal == nil {
+        return nil
+    }
+    if err := g.journal.Write(ids...); err != nil {
+        return fmt.Errorf("idgen: journal: %w", err)
+    }
+    return nil
+}
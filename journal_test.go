@@ -0,0 +1,167 @@
+package idgen_test
+
+import (
+    "bytes"
+    "errors"
+    "io"
+    "os"
+    "path/filepath"
+    "testing"
+
+    "github.com/pascal910107/idgen"
+)
+
+// writeJournal 以 OpenJournal 將 ids 逐一寫為獨立記錄後關閉，回傳檔案內容
+func writeJournal(t *testing.T, path string, ids []idgen.ID) []byte {
+    t.Helper()
+    j, err := idgen.OpenJournal(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    for _, id := range ids {
+        if err := j.Write(id); err != nil {
+            t.Fatal(err)
+        }
+    }
+    if err := j.Close(); err != nil {
+        t.Fatal(err)
+    }
+    data, err := os.ReadFile(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return data
+}
+
+func readJournal(t *testing.T, path string) []idgen.ID {
+    t.Helper()
+    f, err := os.Open(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer f.Close()
+    var ids []idgen.ID
+    r := idgen.NewJournalReader(f)
+    for {
+        id, err := r.Read()
+        if err == io.EOF {
+            return ids
+        }
+        if err != nil {
+            t.Fatalf("read journal entry %d: %v", len(ids), err)
+        }
+        ids = append(ids, id)
+    }
+}
+
+func TestOpenJournalTruncatesTornTail(t *testing.T) {
+    ids := []idgen.ID{
+        idgen.FromParts(0, 1, 1, 2, 0),
+        idgen.FromParts(0, 1, 1, 2, 1),
+        idgen.FromParts(0, 2, 1, 2, 0),
+    }
+    next := idgen.FromParts(0, 3, 1, 2, 0)
+    tests := []struct {
+        name string
+        tear func(valid []byte) []byte
+    }{
+        {"clean", func(b []byte) []byte { return b }},
+        {"partial length", func(b []byte) []byte { return append(b, 0, 0) }},
+        {"partial record", func(b []byte) []byte { return append(b, 0, 0, 0, 16, 1, 2, 3) }},
+        {"zero filled", func(b []byte) []byte { return append(b, make([]byte, 100)...) }},
+        {"last record checksum", func(b []byte) []byte {
+            torn := append([]byte(nil), b...)
+            torn[len(torn)-1] ^= 0xff
+            return torn
+        }},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            path := filepath.Join(t.TempDir(), "ids.journal")
+            valid := writeJournal(t, path, ids)
+            if err := os.WriteFile(path, tt.tear(valid), 0o644); err != nil {
+                t.Fatal(err)
+            }
+            want := ids
+            if tt.name == "last record checksum" { // 最後一筆本身損毀，一併截去
+                want = ids[:2]
+            }
+
+            writeJournal(t, path, []idgen.ID{next})
+            got := readJournal(t, path)
+            want = append(append([]idgen.ID(nil), want...), next)
+            if len(got) != len(want) {
+                t.Fatalf("recovered %d ids, want %d", len(got), len(want))
+            }
+            for i := range want {
+                if got[i] != want[i] {
+                    t.Fatalf("entry %d = %s, want %s", i, got[i], want[i])
+                }
+            }
+        })
+    }
+}
+
+func TestOpenJournalRejectsCorruption(t *testing.T) {
+    ids := []idgen.ID{idgen.FromParts(0, 1, 1, 2, 0), idgen.FromParts(0, 2, 1, 2, 0)}
+    tests := []struct {
+        name    string
+        corrupt func(valid []byte) []byte
+    }{
+        {"not a journal", func([]byte) []byte { return []byte("hello world") }},
+        {"first record checksum", func(b []byte) []byte {
+            c := append([]byte(nil), b...)
+            c[10] ^= 0xff // 第一筆記錄的 ID 內容
+            return c
+        }},
+        {"garbage after torn record", func(b []byte) []byte { return append(b, 0xde, 0xad, 0xbe, 0xef, 1, 2, 3, 4, 5) }},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            path := filepath.Join(t.TempDir(), "ids.journal")
+            data := tt.corrupt(writeJournal(t, path, ids))
+            if err := os.WriteFile(path, data, 0o644); err != nil {
+                t.Fatal(err)
+            }
+            if _, err := idgen.OpenJournal(path); !errors.Is(err, idgen.ErrInvalidEncoding) {
+                t.Fatalf("OpenJournal error = %v, want ErrInvalidEncoding", err)
+            }
+            if after, _ := os.ReadFile(path); !bytes.Equal(after, data) {
+                t.Fatal("OpenJournal modified a corrupt journal")
+            }
+        })
+    }
+}
+
+// countingWriter 記錄 Write 呼叫次數
+type countingWriter struct {
+    bytes.Buffer
+    writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+    w.writes++
+    return w.Buffer.Write(p)
+}
+
+func TestNextNJournalsOneRecord(t *testing.T) {
+    var w countingWriter
+    g, err := idgen.New(idgen.WithJournal(idgen.NewJournalWriter(&w)))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer g.Release()
+    ids, err := g.NextN(100)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if w.writes != 1 {
+        t.Fatalf("NextN(100) wrote %d records, want 1", w.writes)
+    }
+    r := idgen.NewJournalReader(&w.Buffer)
+    for i, want := range ids {
+        if got, err := r.Read(); err != nil || got != want {
+            t.Fatalf("entry %d = %s, %v, want %s", i, got, err, want)
+        }
+    }
+}
@@ -0,0 +1,155 @@
+package idgen
+
+import (
+    "encoding/binary"
+    "fmt"
+    "math"
+)
+
+// ------------- 位元配置 ------------- //
+
+// Layout 描述 128 位元 ID 中各欄位的位元數，依 Epoch、Timestamp、Region、Node、Sequence 由高至低排列
+// 各欄位總和必須為 128；Epoch 1‑16、Timestamp 1‑64、Region 與 Node 0‑32、Sequence 1‑32 bits
+//
+// 非預設配置的 ID 需以同一 Layout 解碼 (Layout.Decode 或 Generator.Decode)，
+// ID 本身的 Epoch、TimestampMillis 等存取方法只適用 DefaultLayout
+type Layout struct {
+    EpochBits     uint8
+    TimestampBits uint8
+    RegionBits    uint8
+    NodeBits      uint8
+    SequenceBits  uint8
+}
+
+// DefaultLayout 為預設的 16/64/16/16/16 配置
+var DefaultLayout = Layout{EpochBits: epochBits, TimestampBits: timestampBits, RegionBits: regionBits, NodeBits: nodeBits, SequenceBits: seqBits}
+
+// LayoutFields 為依 Layout 解碼後的欄位，寬度足以容納任何合法配置
+type LayoutFields struct {
+    Epoch           uint16
+    TimestampMillis uint64
+    Region          uint32
+    Node            uint32
+    Sequence        uint32
+}
+
+// Validate 檢查各欄位位元數是否在允許範圍內且總和為 128
+func (l Layout) Validate() error {
+    switch {
+    case l.EpochBits < 1 || l.EpochBits > 16:
+        return fmt.Errorf("%w: epoch bits %d not in 1-16", ErrInvalidLayout, l.EpochBits)
+    case l.TimestampBits < 1 || l.TimestampBits > 64:
+        return fmt.Errorf("%w: timestamp bits %d not in 1-64", ErrInvalidLayout, l.TimestampBits)
+    case l.RegionBits > 32:
+        return fmt.Errorf("%w: region bits %d not in 0-32", ErrInvalidLayout, l.RegionBits)
+    case l.NodeBits > 32:
+        return fmt.Errorf("%w: node bits %d not in 0-32", ErrInvalidLayout, l.NodeBits)
+    case l.SequenceBits < 1 || l.SequenceBits > 32:
+        return fmt.Errorf("%w: sequence bits %d not in 1-32", ErrInvalidLayout, l.SequenceBits)
+    }
+    if sum := l.EpochBits + l.TimestampBits + l.RegionBits + l.NodeBits + l.SequenceBits; sum != 128 {
+        return fmt.Errorf("%w: bits sum to %d, want 128", ErrInvalidLayout, sum)
+    }
+    return nil
+}
+
+// MaxEpoch 回傳 epoch 欄位可表示的最大值
+func (l Layout) MaxEpoch() uint16 { return uint16(mask64(l.EpochBits)) }
+
+// MaxTimestamp 回傳時間戳欄位可表示的最大毫秒數
+func (l Layout) MaxTimestamp() uint64 { return mask64(l.TimestampBits) }
+
+// MaxRegion 回傳區域 ID 欄位可表示的最大值
+func (l Layout) MaxRegion() uint32 { return uint32(mask64(l.RegionBits)) }
+
+// MaxNode 回傳節點 ID 欄位可表示的最大值
+func (l Layout) MaxNode() uint32 { return uint32(mask64(l.NodeBits)) }
+
+// MaxSequence 回傳序列號欄位可表示的最大值
+func (l Layout) MaxSequence() uint32 { return uint32(mask64(l.SequenceBits)) }
+
+// Encode 依配置組出 ID，超出欄位寬度的高位元會被截斷
+func (l Layout) Encode(f LayoutFields) ID {
+    if l == DefaultLayout {
+        return makeID(f.Epoch, f.TimestampMillis, uint16(f.Region), uint16(f.Node), uint16(f.Sequence))
+    }
+
+    var hi, lo uint64
+    push := func(v uint64, n uint8) {
+        switch {
+        case n == 0:
+        case n == 64:
+            hi, lo = lo, v
+        default:
+            hi = hi<<n | lo>>(64-n)
+            lo = lo<<n | v&mask64(n)
+        }
+    }
+    push(uint64(f.Epoch), l.EpochBits)
+    push(f.TimestampMillis, l.TimestampBits)
+    push(uint64(f.Region), l.RegionBits)
+    push(uint64(f.Node), l.NodeBits)
+    push(uint64(f.Sequence), l.SequenceBits)
+    var id ID
+    binary.BigEndian.PutUint64(id[0:8], hi)
+    binary.BigEndian.PutUint64(id[8:16], lo)
+    return id
+}
+
+// FromParts 同 Encode，但配置不合法或任何欄位超出配置寬度時回傳錯誤而非截斷
+func (l Layout) FromParts(f LayoutFields) (ID, error) {
+    if err := l.Validate(); err != nil {
+        return ID{}, err
+    }
+    switch {
+    case f.Epoch > l.MaxEpoch():
+        return ID{}, fmt.Errorf("%w: epoch %d exceeds %d", ErrInvalidLayout, f.Epoch, l.MaxEpoch())
+    case f.TimestampMillis > l.MaxTimestamp() || f.TimestampMillis > math.MaxInt64:
+        return ID{}, fmt.Errorf("%w: timestamp %d exceeds %d", ErrInvalidLayout, f.TimestampMillis, min(l.MaxTimestamp(), math.MaxInt64))
+    case f.Region > l.MaxRegion():
+        return ID{}, fmt.Errorf("%w: %d not in 0-%d", ErrRegionOutOfRange, f.Region, l.MaxRegion())
+    case f.Node > l.MaxNode():
+        return ID{}, fmt.Errorf("%w: %d not in 0-%d", ErrNodeOutOfRange, f.Node, l.MaxNode())
+    case f.Sequence > l.MaxSequence():
+        return ID{}, fmt.Errorf("%w: sequence %d exceeds %d", ErrInvalidLayout, f.Sequence, l.MaxSequence())
+    }
+    return l.Encode(f), nil
+}
+
+// Decode 依配置拆解 id 的各欄位
+func (l Layout) Decode(id ID) LayoutFields {
+    if l == DefaultLayout {
+        return LayoutFields{
+            Epoch:           id.Epoch(),
+            TimestampMillis: id.TimestampMillis(),
+            Region:          uint32(id.Region()),
+            Node:            uint32(id.Node()),
+            Sequence:        uint32(id.Sequence()),
+        }
+    }
+
+    hi := binary.BigEndian.Uint64(id[0:8])
+    lo := binary.BigEndian.Uint64(id[8:16])
+    pop := func(n uint8) uint64 {
+        switch {
+        case n == 0:
+            return 0
+        case n == 64:
+            v := lo
+            hi, lo = 0, hi
+            return v
+        default:
+            v := lo & mask64(n)
+            lo = lo>>n | hi<<(64-n)
+            hi >>= n
+            return v
+        }
+    }
+    var f LayoutFields
+    f.Sequence = uint32(pop(l.SequenceBits))
+    f.Node = uint32(pop(l.NodeBits))
+    f.Region = uint32(pop(l.RegionBits))
+    f.TimestampMillis = pop(l.TimestampBits)
+    f.Epoch = uint16(pop(l.EpochBits))
+    return f
+}
@@ -0,0 +1,60 @@
+package idgen
+
+import (
+    "context"
+    "fmt"
+)
+
+// ------------- 生命週期 ------------- //
+
+// Close 停止 Generator：之後的 Next 等呼叫回傳 ErrGeneratorClosed，
+// 送出最後一段稽核記錄並將最後發出的時間戳寫回 StateStore，再歸還 NodeIDProvider 取得的節點 ID 並停止背景工作
+// 寫回的是實際發出的最後時間戳而非預留窗口，正常關閉後即使立即重啟也不需提升 epoch
+// 可重複呼叫，第二次起直接回傳 nil；實作 io.Closer
+func (g *Generator) Close() error {
+    g.mu.Lock()
+    if g.closed {
+        g.mu.Unlock()
+        return nil
+    }
+    g.closed = true
+    if g.auditSink != nil {
+        g.flushAudit()
+    }
+    err := g.flush()
+    g.mu.Unlock()
+
+    g.Release()
+    return err
+}
+
+// Shutdown 同 Close，但在 ctx 結束時不再等待並回傳 ctx.Err()，適合交給應用程式的生命週期管理
+// 例如 http.Server.Shutdown 之後或 errgroup 中呼叫；等待中的 Next 完成前 Close 無法取得鎖，
+// 逾時後 Close 仍會於背景完成
+func (g *Generator) Shutdown(ctx context.Context) error {
+    done := make(chan error, 1)
+    go func() { done <- g.Close() }()
+    select {
+    case err := <-done:
+        return err
+    case <-ctx.Done():
+        return ctx.Err()
+    }
+}
+
+// flush 將承諾的時間上限收斂為最後發出的時間戳，呼叫端須持有 g.mu 且已標記 closed
+// 尚未發出任何 ID 時保留啟動時寫入的狀態，其中仍包含重啟前 ID 的時間上限
+func (g *Generator) flush() error {
+    if g.store == nil || g.lastMillis == 0 {
+        return nil
+    }
+    st := State{Epoch: g.epoch, LastMillis: g.lastMillis}
+    if st == g.persisted {
+        return nil
+    }
+    if err := g.store.Save(st); err != nil {
+        return fmt.Errorf("idgen: persist state: %w", err)
+    }
+    g.persisted = st
+    return nil
+}
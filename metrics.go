@@ -0,0 +1,79 @@
+package idgen
+
+import "time"
+
+// ------------- 指標 ------------- //
+
+// Metrics 接收 Generator 的健康指標，不依賴任何指標函式庫
+// 實作需為並發安全；Prometheus 轉接位於 promidgen 子套件，StatsD、Datadog 等可用 MetricsFuncs 直接轉接
+type Metrics interface {
+    IncCounter(name string, delta uint64)
+    ObserveDuration(name string, d time.Duration)
+}
+
+// Generator 回報的指標名稱
+const (
+    MetricIDsIssued         = "ids_issued"         // 已產生的 ID 數量
+    MetricClockRollbacks    = "clock_rollbacks"    // 偵測到時鐘回撥的次數
+    MetricEpochBumps        = "epoch_bumps"        // 因回撥而提升 epoch 的次數
+    MetricSequenceExhausted = "sequence_exhausted" // 同毫秒序列號用盡的次數
+    MetricWaitDuration      = "wait_duration"      // 等待時鐘追上或下一毫秒所花的時間
+    MetricClockDrift        = "clock_drift"        // WithDriftMonitor 偵測到的系統時鐘偏移量 (絕對值)
+)
+
+// nopMetrics 為未設定 Metrics 時的預設實作
+type nopMetrics struct{}
+
+func (nopMetrics) IncCounter(string, uint64)             {}
+func (nopMetrics) ObserveDuration(string, time.Duration) {}
+
+// MetricsFuncs 以函式實作 Metrics，nil 欄位代表忽略該類指標
+//
+//    idgen.WithMetrics(idgen.MetricsFuncs{
+//        Counter:  func(name string, d uint64) { statsd.Count("idgen."+name, int64(d), nil, 1) },
+//        Duration: func(name string, d time.Duration) { statsd.Timing("idgen."+name, d, nil, 1) },
+//    })
+type MetricsFuncs struct {
+    Counter  func(name string, delta uint64)
+    Duration func(name string, d time.Duration)
+}
+
+// IncCounter 實作 Metrics
+func (m MetricsFuncs) IncCounter(name string, delta uint64) {
+    if m.Counter != nil {
+        m.Counter(name, delta)
+    }
+}
+
+// ObserveDuration 實作 Metrics
+func (m MetricsFuncs) ObserveDuration(name string, d time.Duration) {
+    if m.Duration != nil {
+        m.Duration(name, d)
+    }
+}
+
+// MultiMetrics 回傳依序轉發給所有 ms 的 Metrics，nil 元素會被略過
+// 例如同時匯出 Prometheus 與 StatsD
+func MultiMetrics(ms ...Metrics) Metrics {
+    var multi multiMetrics
+    for _, m := range ms {
+        if m != nil {
+            multi = append(multi, m)
+        }
+    }
+    return multi
+}
+
+type multiMetrics []Metrics
+
+func (ms multiMetrics) IncCounter(name string, delta uint64) {
+    for _, m := range ms {
+        m.IncCounter(name, delta)
+    }
+}
+
+func (ms multiMetrics) ObserveDuration(name string, d time.Duration) {
+    for _, m := range ms {
+        m.ObserveDuration(name, d)
+    }
+}
@@ -0,0 +1,42 @@
+package idgen
+
+import "sync/atomic"
+
+// ------------- 特殊 ID ------------- //
+
+// NilID 為全零的 ID，作為「沒有 ID」的哨兵值
+// 正常產生的 ID 不會等於 NilID (時間戳必大於 0)
+var NilID ID
+
+// MinID 與 MaxID 為所有 ID 的下界與上界 (全 0x00 / 全 0xFF)
+// 可作為有序 key-value store 中無上下限範圍查詢的邊界
+var (
+    MinID = ID{}
+    MaxID = ID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+)
+
+// IsZero 回報 id 是否為 NilID
+// encoding/json 的 omitzero 標籤會使用此方法略過空 ID
+func (id ID) IsZero() bool {
+    return id == NilID
+}
+
+// IsNil 與 IsZero 相同，供偏好 UUID 慣用命名的呼叫端使用
+func (id ID) IsNil() bool {
+    return id == NilID
+}
+
+// emptyAsNil 決定空字串與 NilID 是否互相對應，預設關閉
+var emptyAsNil atomic.Bool
+
+// SetEmptyAsNil 設定是否將空字串視為 NilID (並發安全)
+// 開啟後 Parse("") 回傳 NilID，NilID 寫入資料庫時則輸出 NULL，
+// 方便處理可為空的 ID 欄位；關閉時 Parse("") 回傳 ErrInvalidLength
+func SetEmptyAsNil(enabled bool) {
+    emptyAsNil.Store(enabled)
+}
+
+// EmptyAsNil 回報目前是否將空字串視為 NilID
+func EmptyAsNil() bool {
+    return emptyAsNil.Load()
+}
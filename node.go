@@ -0,0 +1,33 @@
+package idgen
+
+import (
+    "hash/fnv"
+    "net"
+)
+
+// ------------- 節點 ID 推導 ------------- //
+
+// NodeIDFromMAC 以主要網路介面的硬體位址雜湊出 16-bit 節點 ID
+// 主要介面為第一個已啟用、非 loopback 且具有硬體位址的介面
+// 僅適合小型叢集：不同機器仍有機率 (約 n²/131072) 映射到相同節點 ID
+func NodeIDFromMAC() (uint16, error) {
+    ifaces, err := net.Interfaces()
+    if err != nil {
+        return 0, err
+    }
+    for _, iface := range ifaces {
+        if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 || len(iface.HardwareAddr) == 0 {
+            continue
+        }
+        return hashNodeID(iface.HardwareAddr), nil
+    }
+    return 0, ErrNoHardwareAddress
+}
+
+// hashNodeID 以 FNV-1a 計算雜湊並將 32 bits 摺疊成 16 bits
+func hashNodeID(b []byte) uint16 {
+    h := fnv.New32a()
+    h.Write(b)
+    sum := h.Sum32()
+    return uint16(sum>>16) ^ uint16(sum)
+}
@@ -0,0 +1,45 @@
+package idgen
+
+import (
+    "crypto/aes"
+    "crypto/cipher"
+    "fmt"
+)
+
+// ------------- 對外混淆 ------------- //
+
+// Obfuscator 以 AES 對 ID 的 16 bytes 做單一區塊加密，是 128 位元空間上的帶金鑰置換：
+// 混淆後的 ID 看不出產生時間、節點拓撲與序列號，也失去排序性；持有金鑰的內部系統可還原為原本可排序的 ID
+// 置換為一對一，混淆後的值不會碰撞，可直接沿用 ID 的各種編碼輸出
+//
+//    o, _ := idgen.NewObfuscator(key) // 16、24 或 32 bytes
+//    public := o.Obfuscate(id).Base32Crockford()
+//    id = o.Deobfuscate(idgen.MustParse(public))
+//
+// 相同的 ID 永遠混淆為相同的值；需要防止偽造時請搭配 IDSigner
+type Obfuscator struct {
+    block cipher.Block
+}
+
+// NewObfuscator 以 AES 金鑰建立 Obfuscator，key 須為 16、24 或 32 bytes
+func NewObfuscator(key []byte) (*Obfuscator, error) {
+    b, err := aes.NewCipher(key)
+    if err != nil {
+        return nil, fmt.Errorf("idgen: obfuscation key: %w", err)
+    }
+    return &Obfuscator{block: b}, nil
+}
+
+// Obfuscate 回傳 id 混淆後的值 (並發安全)
+func (o *Obfuscator) Obfuscate(id ID) ID {
+    var out ID
+    o.block.Encrypt(out[:], id[:])
+    return out
+}
+
+// Deobfuscate 將 Obfuscate 的結果還原為原本的 ID (並發安全)
+func (o *Obfuscator) Deobfuscate(id ID) ID {
+    var out ID
+    o.block.Decrypt(out[:], id[:])
+    return out
+}
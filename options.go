@@ -0,0 +1,198 @@
+package idgen
+
+import (
+    "context"
+    "fmt"
+    "time"
+)
+
+// ------------- 函式選項 ------------- //
+
+// Option 設定 Generator 的可選參數，於 New 建立時依序套用
+type Option func(*Generator) error
+
+// WithRegion 指定區域 ID (0‑65535)
+func WithRegion(regionID uint16) Option {
+    return func(g *Generator) error {
+        g.regionID = uint32(regionID)
+        return nil
+    }
+}
+
+// WithNode 指定同區域內唯一的節點 ID (0‑65535)
+func WithNode(nodeID uint16) Option {
+    return func(g *Generator) error {
+        g.nodeID = uint32(nodeID)
+        return nil
+    }
+}
+
+// WithRegionNode 同時指定區域與節點 ID，接受超過 16 bits 的值，搭配 WithLayout 加寬欄位時使用
+// 範圍依 Layout 於 New 時驗證
+func WithRegionNode(regionID, nodeID uint32) Option {
+    return func(g *Generator) error {
+        g.regionID, g.nodeID = regionID, nodeID
+        return nil
+    }
+}
+
+// WithLayout 指定 ID 的位元配置，未設定時使用 DefaultLayout
+// 非預設配置產生的 ID 須以 Generator.Decode 或 Layout.Decode 解碼
+func WithLayout(l Layout) Option {
+    return func(g *Generator) error {
+        if err := l.Validate(); err != nil {
+            return err
+        }
+        g.layout = l
+        return nil
+    }
+}
+
+// WithEntropyBits 以 crypto/rand 隨機值填入序列號欄位的低 n bits，使 ID 難以猜測，適合出現在公開 URL 的 ID
+// 剩餘高位仍為遞增計數器，因此 ID 依然唯一且遞增，但每毫秒可產生的數量降為 2^(序列號位元數-n)；
+// n 必須小於序列號位元數，需要更多隨機位元時可搭配 WithLayout 加寬序列號
+func WithEntropyBits(n uint8) Option {
+    return func(g *Generator) error {
+        g.entropyBits = n
+        return nil
+    }
+}
+
+// WithPrecision 指定時間戳精度，可為 time.Millisecond (預設) 或 time.Microsecond
+// 微秒精度下同一毫秒內的 ID 順序反映實際產生時間，而非僅依序列號；
+// 時間戳欄位改存微秒數，需以 Generator.Time 解碼時間，且同一 StateStore 不可混用不同精度
+func WithPrecision(unit time.Duration) Option {
+    return func(g *Generator) error {
+        if unit != time.Millisecond && unit != time.Microsecond {
+            return fmt.Errorf("idgen: unsupported precision %v", unit)
+        }
+        g.tick = unit
+        return nil
+    }
+}
+
+// WithCustomEpoch 指定此 Generator 的時間戳起算點，未設定時使用 CustomEpoch
+// 解碼時間需使用相同的起算點
+func WithCustomEpoch(t time.Time) Option {
+    return func(g *Generator) error {
+        g.customEpoch = t.UnixMilli()
+        return nil
+    }
+}
+
+// WithClock 指定時間來源，nil 代表使用系統時鐘
+func WithClock(c Clock) Option {
+    return func(g *Generator) error {
+        if c == nil {
+            c = SystemClock()
+        }
+        g.clock = c
+        return nil
+    }
+}
+
+// WithMonotonicClock 以 NewMonotonicClock(reanchor) 作為時間來源，
+// 系統時間被 NTP 調整或手動修改時不會產生時鐘回撥
+func WithMonotonicClock(reanchor time.Duration) Option {
+    return WithClock(NewMonotonicClock(reanchor))
+}
+
+// WithCachedClock 以 NewCachedClock 包裝目前的時間來源，每隔 refresh 更新一次，適合每秒產生大量 ID 的熱路徑
+// 只包裝在此之前設定的 Clock，需放在 WithClock 或 WithMonotonicClock 之後；背景更新於 Generator.Release 時停止
+func WithCachedClock(refresh time.Duration) Option {
+    return func(g *Generator) error {
+        c, stop := NewCachedClock(g.clock, refresh)
+        g.clock = c
+        g.releases = append(g.releases, stop)
+        return nil
+    }
+}
+
+// WithRollbackPolicy 指定時鐘回撥時的處理策略，預設為 DefaultRollbackPolicy
+func WithRollbackPolicy(p RollbackPolicy) Option {
+    return func(g *Generator) error {
+        g.rollback = p
+        return nil
+    }
+}
+
+// WithAutoNodeID 以 NodeIDFromMAC 自動推導節點 ID，找不到硬體位址時 New 回傳錯誤
+func WithAutoNodeID() Option {
+    return func(g *Generator) error {
+        nodeID, err := NodeIDFromMAC()
+        if err != nil {
+            return err
+        }
+        g.nodeID = uint32(nodeID)
+        return nil
+    }
+}
+
+// WithNodeIDProvider 於 New 時透過 p 取得 region 與 node，覆寫先前設定的值
+// 取得的節點 ID 在呼叫 Generator.Release 後歸還
+func WithNodeIDProvider(ctx context.Context, p NodeIDProvider) Option {
+    return func(g *Generator) error {
+        region, node, release, err := p.Acquire(ctx)
+        if err != nil {
+            return err
+        }
+        g.regionID, g.nodeID = uint32(region), uint32(node)
+        if release != nil {
+            g.releases = append(g.releases, release)
+        }
+        return nil
+    }
+}
+
+// WithMetrics 指定接收指標的 Metrics，nil 代表不回報
+func WithMetrics(m Metrics) Option {
+    return func(g *Generator) error {
+        if m == nil {
+            m = nopMetrics{}
+        }
+        g.metrics = m
+        return nil
+    }
+}
+
+// WithStateStore 啟用狀態持久化：建立時載入 epoch 與時間上限，產生 ID 時定期寫回
+// 可防止程序在時鐘回撥的情況下重啟後重複發出相同的 (timestamp, sequence)
+func WithStateStore(s StateStore) Option {
+    return func(g *Generator) error {
+        g.store = s
+        return nil
+    }
+}
+
+// WithStartupEpochBump 每次程序啟動都將 epoch 加一並寫入 s，使每次啟動產生的 ID 必大於前次，
+// 即使沒有毫秒等級的狀態持久化也能確保跨重啟唯一
+// s 為 nil 時沿用 WithStateStore 設定的 store；兩者皆未設定時 New 回傳錯誤
+// epoch 為 16 bits，約 65536 次重啟後回繞
+func WithStartupEpochBump(s StateStore) Option {
+    return func(g *Generator) error {
+        g.startupBump = true
+        g.epochStore = s
+        return nil
+    }
+}
+
+// WithSequencePolicy 指定序列號用盡時的處理策略，預設為 SequenceWait
+func WithSequencePolicy(p SequencePolicy) Option {
+    return func(g *Generator) error {
+        g.seqPolicy = p
+        return nil
+    }
+}
+
+// WithSpinWait 讓 SequenceWait 在序列號用盡時以自旋 (每次檢查間讓出處理器) 等待下一個時間單位，
+// 取代 time.Sleep；負載高時 Sleep(1ms) 常多睡數毫秒，自旋以 CPU 換取較低的尾端延遲
+// 自旋超過 max 仍未進入下一個時間單位 (例如時鐘停滯) 時退回 sleep；max 不大於 0 時使用 2ms
+func WithSpinWait(max time.Duration) Option {
+    return func(g *Generator) error {
+        if max <= 0 {
+            max = 2 * time.Millisecond
+        }
+        g.spinWait = max
+        return nil
+    }
+}
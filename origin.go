@@ -0,0 +1,73 @@
+package idgen
+
+import (
+    "fmt"
+    "slices"
+)
+
+// ------------- 來源驗證 ------------- //
+
+// FieldRange 為欄位值的閉區間 [Min, Max]，單一值以 Min == Max 表示
+type FieldRange struct {
+    Min, Max uint32
+}
+
+// inRanges 回傳 v 是否落在任一區間內
+func inRanges(ranges []FieldRange, v uint32) bool {
+    return slices.ContainsFunc(ranges, func(r FieldRange) bool { return v >= r.Min && v <= r.Max })
+}
+
+// OriginPolicy 描述部署中實際存在的區域與節點，用於拒絕宣稱來自不存在節點的 ID
+// 例如接收其他服務傳來的 ID 時，避免偽造或設定錯誤的節點產生的 ID 混入
+//
+//    p := idgen.OriginPolicy{
+//        Regions:     []idgen.FieldRange{{1, 3}},
+//        Nodes:       []idgen.FieldRange{{0, 63}},
+//        RegionNodes: map[uint32][]idgen.FieldRange{3: {{0, 7}}}, // 區域 3 只有 8 個節點
+//    }
+//    if err := p.Verify(id); err != nil { ... }
+type OriginPolicy struct {
+    // Layout 為解碼 ID 使用的位元配置，零值代表 DefaultLayout
+    Layout Layout
+    // Regions 為允許的區域 ID，空代表不限制
+    Regions []FieldRange
+    // Nodes 為所有區域共用的允許節點 ID，空代表不限制
+    Nodes []FieldRange
+    // RegionNodes 為個別區域允許的節點 ID，有設定的區域以此取代 Nodes
+    RegionNodes map[uint32][]FieldRange
+}
+
+// Verify 檢查 id 的區域與節點是否在允許範圍內，不符時回傳包裝 ErrUnknownOrigin 的錯誤
+func (p *OriginPolicy) Verify(id ID) error {
+    layout := p.Layout
+    if layout == (Layout{}) {
+        layout = DefaultLayout
+    }
+    f := layout.Decode(id)
+    if len(p.Regions) > 0 && !inRanges(p.Regions, f.Region) {
+        return fmt.Errorf("%w: region %d not allowed", ErrUnknownOrigin, f.Region)
+    }
+    nodes, ok := p.RegionNodes[f.Region]
+    if !ok {
+        nodes = p.Nodes
+    }
+    if len(nodes) > 0 && !inRanges(nodes, f.Node) {
+        return fmt.Errorf("%w: node %d not allowed in region %d", ErrUnknownOrigin, f.Node, f.Region)
+    }
+    return nil
+}
+
+// VerifyOrigin 檢查以預設位元配置產生的 id 是否來自 allowedRegions 中的區域與 allowedNodes 中的節點
+// 任一清單為空代表不限制該欄位；需要範圍或依區域區分節點時改用 OriginPolicy
+func VerifyOrigin(id ID, allowedRegions, allowedNodes []uint16) error {
+    p := OriginPolicy{Regions: singleValues(allowedRegions), Nodes: singleValues(allowedNodes)}
+    return p.Verify(id)
+}
+
+func singleValues(vs []uint16) []FieldRange {
+    ranges := make([]FieldRange, len(vs))
+    for i, v := range vs {
+        ranges[i] = FieldRange{uint32(v), uint32(v)}
+    }
+    return ranges
+}
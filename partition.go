@@ -0,0 +1,49 @@
+package idgen
+
+// ------------- 分區輔助 ------------- //
+
+// Shard 將 id 穩定地對應到 [0, n) 其中一個分區，n <= 0 時 panic
+// 以整個 ID 的雜湊做一致性雜湊 (jump consistent hash)：同一 ID 永遠落在同一分區，
+// 不同 ID 均勻分散；n 增加時只有約 1/n 的 ID 需要搬移
+func (id ID) Shard(n int) int {
+    if n <= 0 {
+        panic("idgen: Shard called with non-positive n")
+    }
+    return int(jumpHash(id.hash64(), int64(n)))
+}
+
+// PartitionKey 回傳 4 bytes 的 region|node (Big‑Endian)，可作為 Kafka 等訊息佇列的 key
+// 同一產生節點的 ID 會落在同一分區，保留節點內的產生順序；
+// 不需要節點親和性時，直接以 Bytes() 作為 key 可讓訊息更均勻分散
+//
+// 僅適用 DefaultLayout，非預設配置請以 Layout.Decode 取出欄位
+func (id ID) PartitionKey() []byte {
+    key := make([]byte, 4)
+    copy(key, id[10:14])
+    return key
+}
+
+// hash64 回傳 id 的 FNV‑1a 64-bit 雜湊
+func (id ID) hash64() uint64 {
+    const (
+        offset64 = 14695981039346656037
+        prime64  = 1099511628211
+    )
+    h := uint64(offset64)
+    for _, b := range id {
+        h ^= uint64(b)
+        h *= prime64
+    }
+    return h
+}
+
+// jumpHash 為 Lamping & Veach 的 jump consistent hash
+func jumpHash(key uint64, buckets int64) int64 {
+    var b, j int64 = -1, 0
+    for j < buckets {
+        b = j
+        key = key*2862933555777941757 + 1
+        j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+    }
+    return b
+}
@@ -0,0 +1,126 @@
+package idgen
+
+import (
+    "errors"
+    "fmt"
+    "sync"
+    "time"
+)
+
+// ------------- 預先產生的 ID 池 ------------- //
+
+// IDPool 於背景 goroutine 預先產生 ID 放入緩衝 channel，取用時只需一次 channel 接收
+// 緩衝量降到低水位時喚醒背景補充至高水位；緩衝已空時退回直接呼叫 Generator
+// Generator 被關閉後背景補充隨即結束，池中剩餘的 ID 取完後 Next 回傳 ErrGeneratorClosed
+//
+// 注意：池中的 ID 時間戳為產生當下而非取用當下，且退回路徑產生的 ID
+// 可能大於仍留在池中的 ID；需要嚴格遞增或精確時間戳的場景請直接使用 Generator
+type IDPool struct {
+    gen    *Generator
+    ch     chan ID
+    low    int
+    refill chan struct{}
+    stop   chan struct{}
+    done   chan struct{}
+    once   sync.Once
+}
+
+// NewPool 以 g 建立 IDPool，緩衝容量為 high，剩餘數量不大於 low 時觸發補充
+// 必須滿足 0 <= low < high；建立後立即開始填充，不再使用時應呼叫 Close
+func NewPool(g *Generator, low, high int) (*IDPool, error) {
+    if low < 0 || high <= low {
+        return nil, fmt.Errorf("idgen: invalid pool watermarks low=%d high=%d", low, high)
+    }
+
+    p := &IDPool{
+        gen:    g,
+        ch:     make(chan ID, high),
+        low:    low,
+        refill: make(chan struct{}, 1),
+        stop:   make(chan struct{}),
+        done:   make(chan struct{}),
+    }
+    p.refill <- struct{}{}
+    go p.run()
+    return p, nil
+}
+
+// Next 從池中取出一個 ID；池已空時直接向 Generator 取號
+func (p *IDPool) Next() (ID, error) {
+    select {
+    case id, ok := <-p.ch:
+        if !ok { // 背景補充因 Generator 關閉而結束
+            return ID{}, ErrGeneratorClosed
+        }
+        if len(p.ch) <= p.low {
+            p.wake()
+        }
+        return id, nil
+    default:
+        p.wake()
+        return p.gen.Next()
+    }
+}
+
+// Len 回傳池中目前可用的 ID 數量
+func (p *IDPool) Len() int {
+    return len(p.ch)
+}
+
+// Close 停止背景補充並捨棄池中剩餘的 ID，可重複呼叫
+// Close 之後的 Next 一律直接向 Generator 取號
+func (p *IDPool) Close() {
+    p.once.Do(func() {
+        close(p.stop)
+        <-p.done
+        for len(p.ch) > 0 {
+            <-p.ch
+        }
+    })
+}
+
+// wake 以不阻塞的方式通知背景補充
+func (p *IDPool) wake() {
+    select {
+    case p.refill <- struct{}{}:
+    default:
+    }
+}
+
+// run 每次被喚醒時將池補滿至高水位，直到 Close
+// 產生失敗 (例如時鐘回撥策略回報錯誤) 時稍後重試，呼叫端於池空時會自行取得錯誤；
+// Generator 已關閉時不再重試，關閉 channel 後結束
+func (p *IDPool) run() {
+    defer close(p.done)
+
+    for {
+        select {
+        case <-p.stop:
+            return
+        case <-p.refill:
+        }
+
+        for n := cap(p.ch) - len(p.ch); n > 0; n = cap(p.ch) - len(p.ch) {
+            ids, err := p.gen.NextN(n)
+            if errors.Is(err, ErrGeneratorClosed) {
+                close(p.ch)
+                return
+            }
+            if err != nil {
+                select {
+                case <-p.stop:
+                    return
+                case <-time.After(time.Millisecond):
+                }
+                continue
+            }
+            for _, id := range ids {
+                select {
+                case p.ch <- id:
+                case <-p.stop:
+                    return
+                }
+            }
+        }
+    }
+}
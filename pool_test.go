@@ -0,0 +1,55 @@
+package idgen_test
+
+import (
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/pascal910107/idgen"
+)
+
+func TestPoolStopsWhenGeneratorClosed(t *testing.T) {
+    g, err := idgen.New()
+    if err != nil {
+        t.Fatal(err)
+    }
+    p, err := idgen.NewPool(g, 2, 8)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer p.Close()
+    for deadline := time.Now().Add(time.Second); p.Len() < 8; time.Sleep(time.Millisecond) {
+        if time.Now().After(deadline) {
+            t.Fatalf("pool filled to %d, want 8", p.Len())
+        }
+    }
+    if err := g.Close(); err != nil {
+        t.Fatal(err)
+    }
+
+    seen := make(map[idgen.ID]bool)
+    for range 8 {
+        id, err := p.Next()
+        if err != nil {
+            t.Fatalf("buffered id: %v", err)
+        }
+        if seen[id] {
+            t.Fatalf("duplicate id %s", id)
+        }
+        seen[id] = true
+    }
+    if _, err := p.Next(); !errors.Is(err, idgen.ErrGeneratorClosed) {
+        t.Fatalf("Next after close error = %v, want ErrGeneratorClosed", err)
+    }
+
+    done := make(chan struct{})
+    go func() {
+        p.Close()
+        close(done)
+    }()
+    select {
+    case <-done:
+    case <-time.After(time.Second):
+        t.Fatal("Close blocked after generator closed")
+    }
+}
@@ -0,0 +1,95 @@
+package idgen
+
+import (
+    "encoding/hex"
+    "fmt"
+    "strings"
+)
+
+// ------------- 帶型別前綴的 ID ------------- //
+
+// prefixSep 分隔前綴與 ID 本體
+const prefixSep = '_'
+
+// maxPrefixLen 為前綴最大長度
+const maxPrefixLen = 32
+
+// Prefix 表示型別前綴，例如 "usr"，用於產生 "usr_<32 字元 hex>" 形式的自描述 ID
+// 前綴限定為小寫英文字母開頭、僅含小寫英文字母與數字，長度 1‑32
+type Prefix string
+
+// NewPrefix 驗證並建立 Prefix
+func NewPrefix(p string) (Prefix, error) {
+    if err := validatePrefix(p); err != nil {
+        return "", err
+    }
+    return Prefix(p), nil
+}
+
+// MustPrefix 同 NewPrefix，驗證失敗時 panic，適合宣告套件層級常數
+func MustPrefix(p string) Prefix {
+    prefix, err := NewPrefix(p)
+    if err != nil {
+        panic(err)
+    }
+    return prefix
+}
+
+// Encode 回傳 "<prefix>_<hex>" 字串
+func (p Prefix) Encode(id ID) string {
+    var b strings.Builder
+    b.Grow(len(p) + 1 + 32)
+    b.WriteString(string(p))
+    b.WriteByte(prefixSep)
+    b.WriteString(id.Hex())
+    return b.String()
+}
+
+// Parse 嚴格解析 Encode 產生的字串：前綴必須相符，本體必須為 32 字元小寫 hex
+func (p Prefix) Parse(s string) (ID, error) {
+    prefix, id, err := ParsePrefixed(s)
+    if err != nil {
+        return ID{}, err
+    }
+    if prefix != p {
+        return ID{}, fmt.Errorf("%w: got %q, want %q", ErrInvalidPrefix, prefix, p)
+    }
+    return id, nil
+}
+
+// ParsePrefixed 解析任意 "<prefix>_<hex>" 字串，回傳前綴與 ID
+func ParsePrefixed(s string) (Prefix, ID, error) {
+    i := strings.LastIndexByte(s, prefixSep)
+    if i < 0 {
+        return "", ID{}, fmt.Errorf("%w: missing separator in %q", ErrInvalidPrefix, s)
+    }
+    prefix, body := s[:i], s[i+1:]
+    if err := validatePrefix(prefix); err != nil {
+        return "", ID{}, err
+    }
+    if len(body) != 32 {
+        return "", ID{}, fmt.Errorf("%w: prefixed id body length %d", ErrInvalidLength, len(body))
+    }
+    for j := 0; j < len(body); j++ { // 嚴格模式只接受小寫，確保字串表示唯一
+        if c := body[j]; !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+            return "", ID{}, fmt.Errorf("%w: prefixed id body %q", ErrInvalidEncoding, body)
+        }
+    }
+    var id ID
+    hex.Decode(id[:], []byte(body))
+    return Prefix(prefix), id, nil
+}
+
+func validatePrefix(p string) error {
+    if len(p) == 0 || len(p) > maxPrefixLen {
+        return fmt.Errorf("%w: length %d not in 1-%d", ErrInvalidPrefix, len(p), maxPrefixLen)
+    }
+    for i := 0; i < len(p); i++ {
+        c := p[i]
+        if 'a' <= c && c <= 'z' || i > 0 && '0' <= c && c <= '9' {
+            continue
+        }
+        return fmt.Errorf("%w: %q", ErrInvalidPrefix, p)
+    }
+    return nil
+}
@@ -0,0 +1,79 @@
+package idgen_test
+
+import (
+    "errors"
+    "strings"
+    "testing"
+
+    "github.com/pascal910107/idgen"
+)
+
+func TestPrefixRoundTrip(t *testing.T) {
+    usr := idgen.MustPrefix("usr")
+    id := idgen.FromParts(1, 123456789, 2, 3, 4)
+
+    s := usr.Encode(id)
+    if want := "usr_" + id.Hex(); s != want {
+        t.Fatalf("Encode = %q, want %q", s, want)
+    }
+    if got, err := usr.Parse(s); err != nil || got != id {
+        t.Fatalf("Parse(%q) = %s, %v", s, got, err)
+    }
+    p, got, err := idgen.ParsePrefixed(s)
+    if err != nil || p != usr || got != id {
+        t.Fatalf("ParsePrefixed(%q) = %q, %s, %v", s, p, got, err)
+    }
+}
+
+func TestPrefixParseRejects(t *testing.T) {
+    usr := idgen.MustPrefix("usr")
+    body := idgen.FromParts(1, 123456789, 2, 3, 4).Hex()
+
+    tests := []struct {
+        name string
+        in   string
+        want error
+    }{
+        {"other prefix", "org_" + body, idgen.ErrInvalidPrefix},
+        {"missing separator", "usr" + body, idgen.ErrInvalidPrefix},
+        {"empty prefix", "_" + body, idgen.ErrInvalidPrefix},
+        {"upper case prefix", "USR_" + body, idgen.ErrInvalidPrefix},
+        {"short body", "usr_" + body[:31], idgen.ErrInvalidLength},
+        {"upper case body", "usr_" + strings.ToUpper(body), idgen.ErrInvalidEncoding},
+        {"non hex body", "usr_" + body[:31] + "g", idgen.ErrInvalidEncoding},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if _, err := usr.Parse(tt.in); !errors.Is(err, tt.want) {
+                t.Fatalf("Parse(%q) error = %v, want %v", tt.in, err, tt.want)
+            }
+        })
+    }
+}
+
+func TestNewPrefix(t *testing.T) {
+    tests := []struct {
+        in string
+        ok bool
+    }{
+        {"usr", true},
+        {"a1", true},
+        {strings.Repeat("a", 32), true},
+        {"", false},
+        {strings.Repeat("a", 33), false},
+        {"1a", false},
+        {"us_r", false},
+        {"Usr", false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.in, func(t *testing.T) {
+            _, err := idgen.NewPrefix(tt.in)
+            if tt.ok && err != nil {
+                t.Fatalf("NewPrefix(%q): %v", tt.in, err)
+            }
+            if !tt.ok && !errors.Is(err, idgen.ErrInvalidPrefix) {
+                t.Fatalf("NewPrefix(%q) error = %v, want ErrInvalidPrefix", tt.in, err)
+            }
+        })
+    }
+}
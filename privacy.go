@@ -0,0 +1,106 @@
+package idgen
+
+import (
+    "crypto/aes"
+    "crypto/cipher"
+    "crypto/rand"
+    "encoding/binary"
+    "fmt"
+    "math/bits"
+    "time"
+)
+
+// ------------- 時間戳模糊化 ------------- //
+
+// timePrivacy 保存 WithTimestampPrivacy 的設定與最近一次的置換結果
+type timePrivacy struct {
+    granularity time.Duration
+    ticks       uint64       // 區間長度 (以 g.tick 為單位)，0 代表未啟用
+    half        uint         // Feistel 每半的位元數，2·half 位元涵蓋 [0, ticks)
+    key         cipher.Block // 以隨機金鑰建立的 AES，作為 Feistel 的輪函式
+
+    ts, out uint64 // 快取最近一次 embed 的輸入與結果，同一時間單位內只計算一次
+    valid   bool
+}
+
+// privacyRounds 為 Feistel 的輪數，4 輪即為強偽隨機置換 (Luby–Rackoff)
+const privacyRounds = 4
+
+// WithTimestampPrivacy 將內嵌的時間戳模糊化到 granularity (例如 time.Second、time.Minute、time.Hour)：
+// 只保留所在區間，區間內的位置改為隨機，適合建立時間屬於敏感資訊的產品
+//
+// 區間內的位置是實際時間經帶隨機金鑰的偽隨機置換 (以 AES 為輪函式的 Feistel，每個區間不同) 所得，
+// 不同時間必定對應不同位置，ID 因此仍然唯一；即使得知部分 ID 的實際時間，也無法推算同區間其他 ID 的時間
+// 同一區間內的 ID 不再遞增，跨區間仍然有序。ID.Time 只能精確到區間，Stats 與 fencing token 不受影響
+// granularity 必須是時間戳精度的整數倍且至少兩個單位；不支援 ReserveBlock
+func WithTimestampPrivacy(granularity time.Duration) Option {
+    return func(g *Generator) error {
+        var key [16]byte
+        if _, err := rand.Read(key[:]); err != nil {
+            return fmt.Errorf("idgen: timestamp privacy key: %w", err)
+        }
+        block, err := aes.NewCipher(key[:])
+        if err != nil {
+            return fmt.Errorf("idgen: timestamp privacy key: %w", err)
+        }
+        g.privacy = timePrivacy{granularity: granularity, key: block}
+        return nil
+    }
+}
+
+// validate 於所有選項套用後依精度換算區間長度
+func (p *timePrivacy) validate(tick time.Duration) error {
+    if p.granularity == 0 {
+        return nil
+    }
+    if p.granularity < 2*tick || p.granularity%tick != 0 {
+        return fmt.Errorf("idgen: timestamp privacy granularity %v must be a multiple of %v and at least 2 units", p.granularity, tick)
+    }
+    p.ticks = uint64(p.granularity / tick)
+    p.half = uint(bits.Len64(p.ticks-1)+1) / 2
+    return nil
+}
+
+// coarse 回傳 ts 所在區間的起點，未啟用時回傳 ts
+func (p *timePrivacy) coarse(ts uint64) uint64 {
+    if p.ticks == 0 {
+        return ts
+    }
+    return ts - ts%p.ticks
+}
+
+// embed 回傳寫入 ID 的時間戳：區間起點加上區間內位置的置換結果，未啟用時回傳 ts
+// Feistel 置換的定義域為 2·half 位元，結果超出區間時重複套用 (cycle walking)，直到落回 [0, ticks)；
+// 限制在區間內的結果仍為一對一，且同一區間永遠得到相同結果，回填或重新進入同一區間時不會與先前的位置衝突
+func (p *timePrivacy) embed(ts uint64) uint64 {
+    if p.ticks == 0 {
+        return ts
+    }
+    if p.valid && ts == p.ts {
+        return p.out
+    }
+    bucket, x := p.coarse(ts), ts%p.ticks
+    for {
+        x = p.feistel(bucket, x)
+        if x < p.ticks {
+            break
+        }
+    }
+    p.ts, p.out, p.valid = ts, bucket+x, true
+    return p.out
+}
+
+// feistel 為 2·half 位元上的置換，輪函式以 AES 加密 (區間, 輪次, 右半) 取得
+func (p *timePrivacy) feistel(bucket, x uint64) uint64 {
+    mask := uint64(1)<<p.half - 1
+    l, r := x>>p.half, x&mask
+    var in, out [aes.BlockSize]byte
+    binary.BigEndian.PutUint64(in[:8], bucket)
+    for round := range privacyRounds {
+        in[8] = byte(round)
+        binary.BigEndian.PutUint32(in[12:], uint32(r))
+        p.key.Encrypt(out[:], in[:])
+        l, r = r, l^binary.BigEndian.Uint64(out[:8])&mask
+    }
+    return l<<p.half | r
+}
@@ -0,0 +1,106 @@
+// Package promidgen 將 idgen.Generator 的健康指標匯出為 Prometheus 指標
+//
+//    c := promidgen.NewCollector("myapp", nil)
+//    prometheus.MustRegister(c)
+//    g, _ := idgen.New(idgen.WithRegion(1), idgen.WithNode(42), idgen.WithMetrics(c))
+package promidgen
+
+import (
+    "time"
+
+    "github.com/pascal910107/idgen"
+    "github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector 同時實作 idgen.Metrics 與 prometheus.Collector
+// 多個 Generator 可共用同一個 Collector，數值會累加
+type Collector struct {
+    issued     prometheus.Counter
+    rollbacks  prometheus.Counter
+    epochBumps prometheus.Counter
+    exhausted  prometheus.Counter
+    wait       prometheus.Histogram
+    drift      prometheus.Histogram
+}
+
+var (
+    _ idgen.Metrics        = (*Collector)(nil)
+    _ prometheus.Collector = (*Collector)(nil)
+)
+
+// NewCollector 建立 Collector，指標名稱為 <namespace>_idgen_*；labels 會附加到所有指標
+func NewCollector(namespace string, labels prometheus.Labels) *Collector {
+    counter := func(name, help string) prometheus.Counter {
+        return prometheus.NewCounter(prometheus.CounterOpts{
+            Namespace:   namespace,
+            Subsystem:   "idgen",
+            Name:        name,
+            Help:        help,
+            ConstLabels: labels,
+        })
+    }
+    return &Collector{
+        issued:     counter("ids_issued_total", "Number of IDs issued."),
+        rollbacks:  counter("clock_rollbacks_total", "Number of clock rollbacks observed."),
+        epochBumps: counter("epoch_bumps_total", "Number of epoch bumps caused by clock rollbacks."),
+        exhausted:  counter("sequence_exhausted_total", "Number of times the per-millisecond sequence was exhausted."),
+        wait: prometheus.NewHistogram(prometheus.HistogramOpts{
+            Namespace:   namespace,
+            Subsystem:   "idgen",
+            Name:        "wait_seconds",
+            Help:        "Time spent waiting for the clock to catch up or for the next millisecond.",
+            ConstLabels: labels,
+            Buckets:     []float64{.0005, .001, .002, .005, .01, .025, .05, .1, .25, .5, 1},
+        }),
+        drift: prometheus.NewHistogram(prometheus.HistogramOpts{
+            Namespace:   namespace,
+            Subsystem:   "idgen",
+            Name:        "clock_drift_seconds",
+            Help:        "Magnitude of wall-clock drift detected by the drift monitor.",
+            ConstLabels: labels,
+            Buckets:     []float64{.01, .05, .1, .5, 1, 5, 10, 60},
+        }),
+    }
+}
+
+// IncCounter 實作 idgen.Metrics
+func (c *Collector) IncCounter(name string, delta uint64) {
+    switch name {
+    case idgen.MetricIDsIssued:
+        c.issued.Add(float64(delta))
+    case idgen.MetricClockRollbacks:
+        c.rollbacks.Add(float64(delta))
+    case idgen.MetricEpochBumps:
+        c.epochBumps.Add(float64(delta))
+    case idgen.MetricSequenceExhausted:
+        c.exhausted.Add(float64(delta))
+    }
+}
+
+// ObserveDuration 實作 idgen.Metrics
+func (c *Collector) ObserveDuration(name string, d time.Duration) {
+    switch name {
+    case idgen.MetricWaitDuration:
+        c.wait.Observe(d.Seconds())
+    case idgen.MetricClockDrift:
+        c.drift.Observe(d.Seconds())
+    }
+}
+
+// Describe 實作 prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+    for _, m := range c.metrics() {
+        m.Describe(ch)
+    }
+}
+
+// Collect 實作 prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+    for _, m := range c.metrics() {
+        m.Collect(ch)
+    }
+}
+
+func (c *Collector) metrics() []prometheus.Collector {
+    return []prometheus.Collector{c.issued, c.rollbacks, c.epochBumps, c.exhausted, c.wait, c.drift}
+}
@@ -0,0 +1,104 @@
+package idgen
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "strconv"
+)
+
+// ------------- 節點 ID 提供者 ------------- //
+
+// NodeIDProvider 負責取得此程序使用的 (region, node)
+// release 用於歸還節點 ID，Generator 不再使用時必須呼叫 (可為 nil)
+// 本套件提供 MAC、環境變數與檔案鎖實作；etcd、Redis 等外部協調實作位於 coordination 子套件
+type NodeIDProvider interface {
+    Acquire(ctx context.Context) (region, node uint16, release func(), err error)
+}
+
+// NodeIDProviderFunc 讓一般函式滿足 NodeIDProvider
+type NodeIDProviderFunc func(ctx context.Context) (region, node uint16, release func(), err error)
+
+// Acquire 呼叫 f 本身
+func (f NodeIDProviderFunc) Acquire(ctx context.Context) (region, node uint16, release func(), err error) {
+    return f(ctx)
+}
+
+// MACProvider 以固定的 Region 搭配 NodeIDFromMAC 推導出的節點 ID
+type MACProvider struct {
+    Region uint16
+}
+
+// Acquire 實作 NodeIDProvider
+func (p MACProvider) Acquire(context.Context) (uint16, uint16, func(), error) {
+    node, err := NodeIDFromMAC()
+    if err != nil {
+        return 0, 0, nil, err
+    }
+    return p.Region, node, nil, nil
+}
+
+// EnvProvider 從環境變數讀取 region 與 node
+// 變數名稱未指定時使用 IDGEN_REGION 與 IDGEN_NODE；region 變數不存在時視為 0
+type EnvProvider struct {
+    RegionVar string
+    NodeVar   string
+}
+
+// Acquire 實作 NodeIDProvider
+func (p EnvProvider) Acquire(context.Context) (uint16, uint16, func(), error) {
+    regionVar, nodeVar := p.RegionVar, p.NodeVar
+    if regionVar == "" {
+        regionVar = "IDGEN_REGION"
+    }
+    if nodeVar == "" {
+        nodeVar = "IDGEN_NODE"
+    }
+
+    var region uint64
+    if v, ok := os.LookupEnv(regionVar); ok {
+        var err error
+        if region, err = strconv.ParseUint(v, 10, regionBits); err != nil {
+            return 0, 0, nil, fmt.Errorf("%w: %s=%q", ErrRegionOutOfRange, regionVar, v)
+        }
+    }
+    v, ok := os.LookupEnv(nodeVar)
+    if !ok {
+        return 0, 0, nil, fmt.Errorf("idgen: environment variable %s not set", nodeVar)
+    }
+    node, err := strconv.ParseUint(v, 10, nodeBits)
+    if err != nil {
+        return 0, 0, nil, fmt.Errorf("%w: %s=%q", ErrNodeOutOfRange, nodeVar, v)
+    }
+    return uint16(region), uint16(node), nil, nil
+}
+
+// FileLockProvider 在 Dir 目錄下以檔案鎖 (flock) 搶占 node-<n>.lock 取得節點 ID
+// 適合同一台主機上的多個程序；程序結束時作業系統自動釋放鎖
+type FileLockProvider struct {
+    Dir     string
+    Region  uint16
+    MaxNode uint16 // 可分配的最大節點 ID，0 代表使用 65535
+}
+
+// Acquire 實作 NodeIDProvider，依序嘗試各節點的鎖檔直到成功
+func (p FileLockProvider) Acquire(ctx context.Context) (uint16, uint16, func(), error) {
+    limit := int(p.MaxNode)
+    if limit == 0 {
+        limit = maxNode
+    }
+    for n := 0; n <= limit; n++ {
+        if err := ctx.Err(); err != nil {
+            return 0, 0, nil, err
+        }
+        path := fmt.Sprintf("%s%cnode-%d.lock", p.Dir, os.PathSeparator, n)
+        release, ok, err := tryLockFile(path)
+        if err != nil {
+            return 0, 0, nil, err
+        }
+        if ok {
+            return p.Region, uint16(n), release, nil
+        }
+    }
+    return 0, 0, nil, fmt.Errorf("idgen: no free node lock in %s", p.Dir)
+}
@@ -0,0 +1,134 @@
+package idgen
+
+import (
+    "errors"
+    "fmt"
+    "slices"
+    "sync"
+)
+
+// ------------- 具名產生器 ------------- //
+
+// Registry 以名稱管理多個 Generator (例如 "orders"、"users" 各自使用不同的區域或位元配置)，並發安全
+// 第一個註冊的 Generator 為預設，可用 SetDefault 變更；Get("") 取得預設 Generator
+type Registry struct {
+    mu   sync.RWMutex
+    gens map[string]*Generator
+    def  string
+}
+
+// NewRegistry 建立空的 Registry
+func NewRegistry() *Registry {
+    return &Registry{gens: make(map[string]*Generator)}
+}
+
+// Register 以 name 註冊 g，name 為空或已被註冊時回傳錯誤
+func (r *Registry) Register(name string, g *Generator) error {
+    if name == "" {
+        return errors.New("idgen: generator name must not be empty")
+    }
+    if g == nil {
+        return fmt.Errorf("idgen: nil generator for %q", name)
+    }
+
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    if _, ok := r.gens[name]; ok {
+        return fmt.Errorf("idgen: generator %q already registered", name)
+    }
+    r.gens[name] = g
+    if r.def == "" {
+        r.def = name
+    }
+    return nil
+}
+
+// RegisterNew 以 opts 建立 Generator 並以 name 註冊；註冊失敗時會 Release 新建立的 Generator
+func (r *Registry) RegisterNew(name string, opts ...Option) (*Generator, error) {
+    g, err := New(opts...)
+    if err != nil {
+        return nil, err
+    }
+    if err := r.Register(name, g); err != nil {
+        g.Release()
+        return nil, err
+    }
+    return g, nil
+}
+
+// Get 回傳 name 對應的 Generator，name 為空時回傳預設 Generator
+func (r *Registry) Get(name string) (*Generator, bool) {
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+    if name == "" {
+        name = r.def
+    }
+    g, ok := r.gens[name]
+    return g, ok
+}
+
+// Default 回傳預設 Generator，尚未註冊任何 Generator 時回傳 nil
+func (r *Registry) Default() *Generator {
+    g, _ := r.Get("")
+    return g
+}
+
+// SetDefault 將 name 設為預設 Generator，name 尚未註冊時回傳 ErrGeneratorNotFound
+func (r *Registry) SetDefault(name string) error {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    if _, ok := r.gens[name]; !ok {
+        return fmt.Errorf("%w: %q", ErrGeneratorNotFound, name)
+    }
+    r.def = name
+    return nil
+}
+
+// Next 以 name 對應的 Generator 產生 ID，name 為空時使用預設 Generator
+func (r *Registry) Next(name string) (ID, error) {
+    g, ok := r.Get(name)
+    if !ok {
+        return ID{}, fmt.Errorf("%w: %q", ErrGeneratorNotFound, name)
+    }
+    return g.Next()
+}
+
+// Names 回傳所有已註冊的名稱 (依字母排序)
+func (r *Registry) Names() []string {
+    r.mu.RLock()
+    names := make([]string, 0, len(r.gens))
+    for name := range r.gens {
+        names = append(names, name)
+    }
+    r.mu.RUnlock()
+    slices.Sort(names)
+    return names
+}
+
+// Release 移除並 Release 所有已註冊的 Generator
+func (r *Registry) Release() {
+    r.mu.Lock()
+    gens := r.gens
+    r.gens, r.def = make(map[string]*Generator), ""
+    r.mu.Unlock()
+
+    for _, g := range gens {
+        g.Release()
+    }
+}
+
+// Close 移除並 Close 所有已註冊的 Generator，回傳合併後的錯誤
+func (r *Registry) Close() error {
+    r.mu.Lock()
+    gens := r.gens
+    r.gens, r.def = make(map[string]*Generator), ""
+    r.mu.Unlock()
+
+    var errs []error
+    for name, g := range gens {
+        if err := g.Close(); err != nil {
+            errs = append(errs, fmt.Errorf("%s: %w", name, err))
+        }
+    }
+    return errors.Join(errs...)
+}
@@ -0,0 +1,29 @@
+package idgen
+
+import "time"
+
+// ------------- 時鐘回撥策略 ------------- //
+
+// RollbackPolicy 決定 Generator 偵測到時鐘回撥時的處理方式
+type RollbackPolicy struct {
+    maxWait time.Duration // 回撥幅度不超過此值時先等待時鐘追上
+    fail    bool          // 無法追上時回傳 ErrClockRollback，而非提升 epoch
+}
+
+// DefaultRollbackPolicy 為預設策略：回撥 5ms 以內等待，超過則提升 epoch
+var DefaultRollbackPolicy = WaitUpTo(5 * time.Millisecond)
+
+// WaitUpTo 回撥幅度不超過 d 時等待時鐘追上；超過 d 或等待後仍落後則提升 epoch
+func WaitUpTo(d time.Duration) RollbackPolicy {
+    return RollbackPolicy{maxWait: d}
+}
+
+// BumpEpoch 一律立即提升 epoch，不做任何等待
+func BumpEpoch() RollbackPolicy {
+    return RollbackPolicy{}
+}
+
+// ReturnError 一律立即回傳 ErrClockRollback，適合寧可失敗並告警的部署
+func ReturnError() RollbackPolicy {
+    return RollbackPolicy{fail: true}
+}
@@ -0,0 +1,53 @@
+package idgen
+
+import "fmt"
+
+// ------------- 分散鍵 ------------- //
+
+// ScatterKeyLen 為 ScatterKey 的長度：1 byte 分桶前綴 + 16 bytes ID
+const ScatterKeyLen = 1 + len(ID{})
+
+// ScatterKey 回傳加上分桶前綴的 17-byte 鍵，用於 Bigtable、HBase、Spanner 等依鍵排序分片的儲存，
+// 避免遞增的時間前綴讓寫入集中在最後一個分片 (salting)
+//
+// 前綴為 id.Shard(buckets)，同一 ID 永遠得到相同的鍵；每個分桶內仍依 ID 排序，
+// 時間範圍查詢可改為對各分桶平行掃描 (見 ScatterRanges)。buckets 須在 1‑256，否則 panic
+func (id ID) ScatterKey(buckets int) []byte {
+    checkScatterBuckets(buckets)
+    key := make([]byte, ScatterKeyLen)
+    key[0] = byte(id.Shard(buckets))
+    copy(key[1:], id[:])
+    return key
+}
+
+// UnscatterKey 移除 ScatterKey 的分桶前綴，還原原始 ID
+func UnscatterKey(key []byte) (ID, error) {
+    var id ID
+    if len(key) != ScatterKeyLen {
+        return id, fmt.Errorf("%w: scatter key has %d bytes, want %d", ErrInvalidLength, len(key), ScatterKeyLen)
+    }
+    copy(id[:], key[1:])
+    return id, nil
+}
+
+// ScatterRanges 回傳各分桶中涵蓋 [from, to] 的起訖鍵 (皆包含)，共 buckets 組
+// 搭配 MinIDAt / MaxIDAt 可將時間範圍查詢展開為每個分桶一次掃描
+func ScatterRanges(buckets int, from, to ID) [][2][]byte {
+    checkScatterBuckets(buckets)
+    ranges := make([][2][]byte, buckets)
+    for b := range ranges {
+        start := make([]byte, ScatterKeyLen)
+        end := make([]byte, ScatterKeyLen)
+        start[0], end[0] = byte(b), byte(b)
+        copy(start[1:], from[:])
+        copy(end[1:], to[:])
+        ranges[b] = [2][]byte{start, end}
+    }
+    return ranges
+}
+
+func checkScatterBuckets(buckets int) {
+    if buckets < 1 || buckets > 256 {
+        panic(fmt.Sprintf("idgen: scatter buckets %d not in 1-256", buckets))
+    }
+}
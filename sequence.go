@@ -0,0 +1,58 @@
+package idgen
+
+import (
+    "encoding/binary"
+    "hash/maphash"
+)
+
+// ------------- 序列號用盡策略 ------------- //
+
+// SequencePolicy 決定同一毫秒內序列號 (65536 個) 用盡時的處理方式
+type SequencePolicy int
+
+const (
+    // SequenceWait 等待時鐘進入下一毫秒 (預設)
+    SequenceWait SequencePolicy = iota
+    // SequenceFail 立即回傳 ErrSequenceExhausted
+    SequenceFail
+    // SequenceBorrow 不等待，直接借用下一毫秒繼續發放 (邏輯時間前進)
+    // 持續超過每毫秒 65536 個時，時間戳會領先實際時間，待負載下降後由實際時間追上
+    SequenceBorrow
+)
+
+// ------------- 隨機序列號起點 ------------- //
+
+// seqStart 保存 WithRandomSequenceStart 的設定與快取的起點
+type seqStart struct {
+    enabled bool
+    seed    maphash.Seed
+
+    ts    uint64 // 快取的時間戳與其起點
+    off   uint32
+    valid bool
+}
+
+// WithRandomSequenceStart 讓每個時間戳的序列號自隨機起點開始 (在序列號欄位內回繞)，而非從 0 開始，
+// 使公開的相鄰 ID 無法透露每毫秒實際發出的數量
+//
+// 起點由時間戳與每個 Generator 各自的隨機種子推導，同一時間戳永遠相同，序列號因此仍然唯一；
+// 但同一時間戳內回繞後的 ID 不再遞增，跨時間戳仍然有序。每毫秒可產生的數量不變；不支援 ReserveBlock
+func WithRandomSequenceStart() Option {
+    return func(g *Generator) error {
+        g.seqStart = seqStart{enabled: true, seed: maphash.MakeSeed()}
+        return nil
+    }
+}
+
+// apply 將計數器 counter 依時間戳 ts 的起點平移後回繞到 mask 範圍內，未啟用時原樣回傳
+func (s *seqStart) apply(ts uint64, counter, mask uint32) uint32 {
+    if !s.enabled {
+        return counter
+    }
+    if !s.valid || s.ts != ts {
+        var buf [8]byte
+        binary.BigEndian.PutUint64(buf[:], ts)
+        s.ts, s.off, s.valid = ts, uint32(maphash.Bytes(s.seed, buf[:])), true
+    }
+    return (counter + s.off) & mask
+}
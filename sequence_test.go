@@ -0,0 +1,70 @@
+package idgen_test
+
+import (
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/pascal910107/idgen"
+    "github.com/pascal910107/idgen/testclock"
+)
+
+func TestRandomSequenceStartUnique(t *testing.T) {
+    tests := []struct {
+        name    string
+        opts    []idgen.Option
+        entropy uint8
+        perTick int
+    }{
+        {"full sequence", nil, 0, 1 << 16},
+        {"entropy bits", []idgen.Option{idgen.WithEntropyBits(4)}, 4, 1 << 12},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            g, clock, err := testclock.NewGenerator(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+                append(tt.opts, idgen.WithRandomSequenceStart(), idgen.WithSequencePolicy(idgen.SequenceFail))...)
+            if err != nil {
+                t.Fatal(err)
+            }
+            defer g.Release()
+
+            seen := make(map[idgen.ID]bool)
+            var prevMax idgen.ID
+            starts := 0
+            for tick := range 3 {
+                // 每個時間戳用盡全部序列號，回繞後仍不可重複
+                ids, err := g.NextN(tt.perTick)
+                if err != nil {
+                    t.Fatal(err)
+                }
+                if _, err := g.Next(); !errors.Is(err, idgen.ErrSequenceExhausted) {
+                    t.Fatalf("tick %d: Next after %d ids error = %v, want ErrSequenceExhausted", tick, tt.perTick, err)
+                }
+                tickMin, tickMax := idgen.MaxID, idgen.MinID
+                for _, id := range ids {
+                    if seen[id] {
+                        t.Fatalf("duplicate id %s", id)
+                    }
+                    seen[id] = true
+                    if id.Less(tickMin) {
+                        tickMin = id
+                    }
+                    if tickMax.Less(id) {
+                        tickMax = id
+                    }
+                }
+                if g.Decode(ids[0]).Sequence>>tt.entropy != 0 {
+                    starts++
+                }
+                if tickMin.Compare(prevMax) <= 0 {
+                    t.Fatalf("tick %d: ids not after previous timestamp", tick)
+                }
+                prevMax = tickMax
+                clock.Advance(time.Millisecond)
+            }
+            if starts == 0 {
+                t.Fatal("every timestamp started at sequence 0")
+            }
+        })
+    }
+}
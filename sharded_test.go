@@ -0,0 +1,63 @@
+package idgen_test
+
+import (
+    "sync"
+    "testing"
+
+    "github.com/pascal910107/idgen"
+)
+
+func TestShardedUnique(t *testing.T) {
+    s, err := idgen.NewSharded(4, idgen.WithRegionNode(1, 8))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer s.Release()
+
+    const workers, perWorker = 8, 20_000
+    results := make([][]idgen.ID, workers)
+    var wg sync.WaitGroup
+    for w := range workers {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for range perWorker {
+                id, err := s.Next()
+                if err != nil {
+                    t.Error(err)
+                    return
+                }
+                results[w] = append(results[w], id)
+            }
+        }()
+    }
+    wg.Wait()
+
+    seen := make(map[idgen.ID]bool, workers*perWorker)
+    for _, ids := range results {
+        for _, id := range ids {
+            if seen[id] {
+                t.Fatalf("duplicate id %s", id)
+            }
+            seen[id] = true
+            if n := id.Node(); n < 8 || n >= 12 {
+                t.Fatalf("id %s has node %d outside shard range 8-11", id, n)
+            }
+        }
+    }
+}
+
+// BenchmarkShardedParallel 與 BenchmarkGeneratorParallel (atomic_test.go) 比較，
+// 以 -cpu 1,4,16,64 觀察分片在多核心上的擴展
+func BenchmarkShardedParallel(b *testing.B) {
+    s, err := idgen.NewSharded(0)
+    if err != nil {
+        b.Fatal(err)
+    }
+    defer s.Release()
+    b.RunParallel(func(pb *testing.PB) {
+        for pb.Next() {
+            s.Next()
+        }
+    })
+}
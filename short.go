@@ -0,0 +1,62 @@
+package idgen
+
+import (
+    "fmt"
+    "slices"
+)
+
+// ------------- 簡短顯示 ------------- //
+
+// Short 回傳 hex 字串的最後 n 個字元，作為介面與日誌中的簡短代號，n 超出 1‑32 時 panic
+// 與 git 的短雜湊不同，取的是結尾而非開頭：開頭為 epoch 與時間戳高位，相近時間產生的 ID 幾乎完全相同，
+// 結尾則是序列號、節點與時間戳低位，變化最大
+func (id ID) Short(n int) string {
+    if n < 1 || n > 32 {
+        panic(fmt.Sprintf("idgen: short length %d not in 1-32", n))
+    }
+    return id.Hex()[32-n:]
+}
+
+// HasShort 回傳 short 是否為 id 的簡短代號 (hex 字串的結尾，不分大小寫)，可用於以代號查找 ID
+func (id ID) HasShort(short string) bool {
+    if len(short) == 0 || len(short) > 32 {
+        return false
+    }
+    hex := id.Hex()[32-len(short):]
+    for i := 0; i < len(short); i++ {
+        c := short[i]
+        if c >= 'A' && c <= 'F' {
+            c += 'a' - 'A'
+        }
+        if c != hex[i] {
+            return false
+        }
+    }
+    return true
+}
+
+// ShortLen 回傳讓 ids 中每個不同的 ID 的 Short 互不相同所需的最短長度，
+// 類似 git 自動決定縮寫長度，儀表板可據此顯示精簡但不會混淆的代號；重複的 ID 視為同一個，ids 為空時回傳 1
+func ShortLen(ids []ID) int {
+    // 將每個 ID 的 hex 由結尾往前排列，排序後只需比較相鄰兩者的共同長度
+    keys := make([][32]byte, len(ids))
+    for i, id := range ids {
+        h := id.Hex()
+        for j := range keys[i] {
+            keys[i][j] = h[31-j]
+        }
+    }
+    slices.SortFunc(keys, func(a, b [32]byte) int { return slices.Compare(a[:], b[:]) })
+
+    n := 1
+    for i := 1; i < len(keys); i++ {
+        common := 0
+        for common < 32 && keys[i][common] == keys[i-1][common] {
+            common++
+        }
+        if common < 32 {
+            n = max(n, common+1)
+        }
+    }
+    return n
+}
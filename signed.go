@@ -0,0 +1,74 @@
+package idgen
+
+import (
+    "crypto/hmac"
+    "crypto/sha256"
+    "encoding/base64"
+    "fmt"
+)
+
+// ------------- 帶簽章的 ID ------------- //
+
+const (
+    signedMACLen = 8                     // 截斷後的 HMAC‑SHA256 長度
+    signedLen    = 32                    // base64url(ID | MAC) 的字元數
+    signedDomain = "idgen signed id\x00" // 簽章前綴，避免與其他用途 (例如游標) 共用金鑰時互相通用
+)
+
+// IDSigner 為對外公開的 ID 附加截斷的 HMAC，使外部無法偽造 ID，也無法靠遞增序列號列舉其他 ID
+// 輸出為 32 字元的 base64url 字串 (ID 16 bytes | HMAC‑SHA256 前 8 bytes)；ID 本身未加密，
+// 仍可看出產生時間與節點
+//
+//    s, _ := idgen.NewIDSigner(key)
+//    public := s.Sign(id)
+//    id, err := s.Verify(public) // 竄改或偽造時回傳 ErrInvalidSignature
+type IDSigner struct {
+    keys [][]byte
+}
+
+// NewIDSigner 以 key 建立 IDSigner，key 至少 16 bytes
+// oldKeys 僅用於驗證，輪替金鑰時可讓先前簽發的 ID 在過渡期內仍然有效
+func NewIDSigner(key []byte, oldKeys ...[]byte) (*IDSigner, error) {
+    s := &IDSigner{}
+    for _, k := range append([][]byte{key}, oldKeys...) {
+        if len(k) < cursorMinKey {
+            return nil, fmt.Errorf("idgen: signing key must be at least %d bytes", cursorMinKey)
+        }
+        s.keys = append(s.keys, append([]byte(nil), k...))
+    }
+    return s, nil
+}
+
+// Sign 回傳 id 的簽章字串
+func (s *IDSigner) Sign(id ID) string {
+    buf := make([]byte, 0, len(id)+signedMACLen)
+    buf = append(buf, id[:]...)
+    buf = append(buf, s.mac(s.keys[0], id)...)
+    return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// Verify 解析 Sign 產生的字串並驗證簽章，格式錯誤或簽章不符時回傳 ErrInvalidSignature
+func (s *IDSigner) Verify(signed string) (ID, error) {
+    var id ID
+    if len(signed) != signedLen {
+        return id, fmt.Errorf("%w: length %d", ErrInvalidSignature, len(signed))
+    }
+    var raw [16 + signedMACLen]byte
+    if _, err := base64.RawURLEncoding.Decode(raw[:], []byte(signed)); err != nil {
+        return id, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+    }
+    copy(id[:], raw[:16])
+    for _, k := range s.keys {
+        if hmac.Equal(s.mac(k, id), raw[16:]) {
+            return id, nil
+        }
+    }
+    return ID{}, fmt.Errorf("%w: signature mismatch", ErrInvalidSignature)
+}
+
+func (s *IDSigner) mac(key []byte, id ID) []byte {
+    h := hmac.New(sha256.New, key)
+    h.Write([]byte(signedDomain))
+    h.Write(id[:])
+    return h.Sum(nil)[:signedMACLen]
+}
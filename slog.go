@@ -0,0 +1,29 @@
+package idgen
+
+import "log/slog"
+
+// ------------- log/slog 整合 ------------- //
+
+var _ slog.LogValuer = ID{}
+
+// LogValue 實作 slog.LogValuer，輸出 hex 字串與解碼後的時間、區域、節點
+//
+//    logger.Info("created", "order_id", id)
+//    // order_id.id=0000... order_id.time=2025-06-01T00:00:00Z order_id.region=1 order_id.node=2
+func (id ID) LogValue() slog.Value {
+    return slog.GroupValue(
+        slog.String("id", id.Hex()),
+        slog.Time("time", id.Time()),
+        slog.Uint64("region", uint64(id.Region())),
+        slog.Uint64("node", uint64(id.Node())),
+    )
+}
+
+// WithLogger 指定記錄降級事件的 logger：時鐘回撥與 epoch 提升記錄為 Warn，序列號用盡記錄為 Debug
+// nil 代表不記錄 (預設)
+func WithLogger(l *slog.Logger) Option {
+    return func(g *Generator) error {
+        g.logger = l
+        return nil
+    }
+}
@@ -0,0 +1,102 @@
+package idgen
+
+import (
+    "errors"
+    "fmt"
+)
+
+// ------------- Twitter Snowflake 互通 ------------- //
+
+// SnowflakeLayout 描述 64-bit Snowflake ID 的位元配置 (最高位為符號位元固定為 0)
+// 各欄位位元數總和必須為 63；Datacenter/Worker/Sequence 各自不得超過 16 bits
+type SnowflakeLayout struct {
+    Epoch          int64 // 時間戳起算點 (Unix 毫秒)
+    TimestampBits  uint8
+    DatacenterBits uint8
+    WorkerBits     uint8
+    SequenceBits   uint8
+}
+
+// TwitterSnowflake 為 Twitter 原始配置：41-bit 時間戳、5-bit datacenter、5-bit worker、12-bit 序列號
+var TwitterSnowflake = SnowflakeLayout{
+    Epoch:          1288834974657,
+    TimestampBits:  41,
+    DatacenterBits: 5,
+    WorkerBits:     5,
+    SequenceBits:   12,
+}
+
+func (l SnowflakeLayout) validate() error {
+    if int(l.TimestampBits)+int(l.DatacenterBits)+int(l.WorkerBits)+int(l.SequenceBits) != 63 {
+        return errors.New("idgen: snowflake layout bits must sum to 63")
+    }
+    if l.DatacenterBits > regionBits || l.WorkerBits > nodeBits || l.SequenceBits > seqBits {
+        return errors.New("idgen: snowflake datacenter, worker and sequence must fit 16 bits")
+    }
+    return nil
+}
+
+// FromSnowflake 將 Snowflake ID 轉為 ID：datacenter → region、worker → node、sequence → sequence
+// 時間以 layout.Epoch 換算為絕對時間後再相對 CustomEpoch 儲存，epoch 欄位為 0
+// 時間早於 CustomEpoch 時回傳 ErrInvalidEncoding
+func FromSnowflake(v int64, layout SnowflakeLayout) (ID, error) {
+    if err := layout.validate(); err != nil {
+        return ID{}, err
+    }
+    if v < 0 {
+        return ID{}, fmt.Errorf("%w: negative snowflake %d", ErrInvalidEncoding, v)
+    }
+
+    u := uint64(v)
+    seq := u & mask64(layout.SequenceBits)
+    u >>= layout.SequenceBits
+    worker := u & mask64(layout.WorkerBits)
+    u >>= layout.WorkerBits
+    dc := u & mask64(layout.DatacenterBits)
+    u >>= layout.DatacenterBits
+
+    unixMs := layout.Epoch + int64(u)
+    if unixMs < CustomEpoch {
+        return ID{}, fmt.Errorf("%w: snowflake timestamp before custom epoch", ErrInvalidEncoding)
+    }
+    return makeID(0, uint64(unixMs-CustomEpoch), uint16(dc), uint16(worker), uint16(seq)), nil
+}
+
+// ToSnowflake 將 ID 轉為 Snowflake ID (有損)：epoch 欄位被捨棄
+// region/node/sequence 或時間戳超出 layout 可容納範圍時回傳 ErrInvalidEncoding，
+// 不會默默截斷而造成碰撞
+func (id ID) ToSnowflake(layout SnowflakeLayout) (int64, error) {
+    if err := layout.validate(); err != nil {
+        return 0, err
+    }
+
+    unixMs := CustomEpoch + int64(id.TimestampMillis())
+    if id.TimestampMillis() >= 1<<63 || unixMs < layout.Epoch {
+        return 0, fmt.Errorf("%w: timestamp before snowflake epoch", ErrInvalidEncoding)
+    }
+    ts := uint64(unixMs - layout.Epoch)
+    switch {
+    case ts > mask64(layout.TimestampBits):
+        return 0, fmt.Errorf("%w: timestamp overflows %d bits", ErrInvalidEncoding, layout.TimestampBits)
+    case uint64(id.Region()) > mask64(layout.DatacenterBits):
+        return 0, fmt.Errorf("%w: region %d overflows %d bits", ErrInvalidEncoding, id.Region(), layout.DatacenterBits)
+    case uint64(id.Node()) > mask64(layout.WorkerBits):
+        return 0, fmt.Errorf("%w: node %d overflows %d bits", ErrInvalidEncoding, id.Node(), layout.WorkerBits)
+    case uint64(id.Sequence()) > mask64(layout.SequenceBits):
+        return 0, fmt.Errorf("%w: sequence %d overflows %d bits", ErrInvalidEncoding, id.Sequence(), layout.SequenceBits)
+    }
+
+    v := ts
+    v = v<<layout.DatacenterBits | uint64(id.Region())
+    v = v<<layout.WorkerBits | uint64(id.Node())
+    v = v<<layout.SequenceBits | uint64(id.Sequence())
+    return int64(v), nil
+}
+
+// mask64 回傳低 n bits 全為 1 的遮罩
+func mask64(n uint8) uint64 {
+    if n >= 64 {
+        return ^uint64(0)
+    }
+    return 1<<n - 1
+}
@@ -0,0 +1,38 @@
+package idgen
+
+import (
+    "slices"
+    "sort"
+)
+
+// ------------- 排序工具 ------------- //
+
+// IDSlice 實作 sort.Interface，依 ID.Compare 的順序遞增排序
+type IDSlice []ID
+
+func (s IDSlice) Len() int           { return len(s) }
+func (s IDSlice) Less(i, j int) bool { return s[i].Less(s[j]) }
+func (s IDSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// Sort 就地遞增排序
+func (s IDSlice) Sort() { SortIDs(s) }
+
+var _ sort.Interface = IDSlice(nil)
+
+// SortIDs 就地將 ids 遞增排序
+func SortIDs(ids []ID) {
+    slices.SortFunc(ids, ID.Compare)
+}
+
+// SearchIDs 在已遞增排序的 ids 中二分搜尋 id，回傳其索引；
+// 不存在時回傳應插入的位置 (可能為 len(ids))，語意同 sort.SearchStrings
+func SearchIDs(ids []ID, id ID) int {
+    i, _ := slices.BinarySearchFunc(ids, id, ID.Compare)
+    return i
+}
+
+// Dedup 就地排序並移除重複的 ID，回傳去重後的切片 (與 ids 共用底層陣列)
+func Dedup(ids []ID) []ID {
+    SortIDs(ids)
+    return slices.Compact(ids)
+}
@@ -0,0 +1,69 @@
+package idgen
+
+import (
+    "database/sql/driver"
+    "fmt"
+    "sync/atomic"
+)
+
+// ------------- database/sql 整合 ------------- //
+
+// sqlFormat 保存 Value 使用的儲存格式，預設為原始 bytes
+var sqlFormat atomic.Int32
+
+// SetSQLFormat 設定 ID.Value 寫入資料庫時使用的格式 (並發安全)
+// Postgres uuid 欄位建議 FormatUUID；MySQL BINARY(16) 使用預設 FormatBinary
+func SetSQLFormat(f Format) {
+    sqlFormat.Store(int32(f))
+}
+
+// SQLFormat 回傳目前 ID.Value 使用的格式
+func SQLFormat() Format {
+    return Format(sqlFormat.Load())
+}
+
+// Value 實作 driver.Valuer，依 SQLFormat 輸出對應表示
+// 開啟 SetEmptyAsNil 時 NilID 寫入為 NULL
+func (id ID) Value() (driver.Value, error) {
+    if id.IsNil() && EmptyAsNil() {
+        return nil, nil
+    }
+    f := SQLFormat()
+    if f == FormatBinary {
+        return id.Bytes(), nil
+    }
+    return id.Encode(f)
+}
+
+// Scan 實作 sql.Scanner
+// 接受 16-byte 原始值或 Parse 可辨識的文字；NULL 會得到零值 ID
+func (id *ID) Scan(src any) error {
+    switch v := src.(type) {
+    case nil:
+        *id = ID{}
+        return nil
+    case []byte:
+        if len(v) == 22 && SQLFormat() == FormatBase64Sortable {
+            return id.scanString(string(v))
+        }
+        parsed, err := ParseBytes(v)
+        if err != nil {
+            return err
+        }
+        *id = parsed
+        return nil
+    case string:
+        return id.scanString(v)
+    default:
+        return fmt.Errorf("%w: cannot scan %T into idgen.ID", ErrInvalidEncoding, src)
+    }
+}
+
+func (id *ID) scanString(s string) error {
+    parsed, err := parseAs(s, SQLFormat())
+    if err != nil {
+        return err
+    }
+    *id = parsed
+    return nil
+}
@@ -0,0 +1,91 @@
+package idgen_test
+
+import (
+    "errors"
+    "testing"
+
+    "github.com/pascal910107/idgen"
+)
+
+func TestSQLValueScanRoundTrip(t *testing.T) {
+    defer idgen.SetSQLFormat(idgen.SQLFormat())
+    id := idgen.FromParts(1, 123456789, 2, 3, 4)
+
+    formats := []idgen.Format{
+        idgen.FormatBinary,
+        idgen.FormatHex,
+        idgen.FormatUUID,
+        idgen.FormatBase64URL,
+        idgen.FormatBase32Crockford,
+        idgen.FormatBase64Sortable,
+        idgen.FormatBase32Check,
+    }
+    for _, f := range formats {
+        t.Run(f.String(), func(t *testing.T) {
+            idgen.SetSQLFormat(f)
+            v, err := id.Value()
+            if err != nil {
+                t.Fatal(err)
+            }
+            // 驅動程式可能以 string 或 []byte 回傳文字欄位
+            srcs := []any{v}
+            switch v := v.(type) {
+            case string:
+                srcs = append(srcs, []byte(v))
+            case []byte:
+                if f != idgen.FormatBinary {
+                    t.Fatalf("Value returned %T for %s", v, f)
+                }
+            default:
+                t.Fatalf("Value returned %T", v)
+            }
+            for _, src := range srcs {
+                var got idgen.ID
+                if err := got.Scan(src); err != nil {
+                    t.Fatalf("Scan(%T): %v", src, err)
+                }
+                if got != id {
+                    t.Fatalf("Scan(%T) = %s, want %s", src, got, id)
+                }
+            }
+        })
+    }
+}
+
+func TestSQLNull(t *testing.T) {
+    defer idgen.SetEmptyAsNil(idgen.EmptyAsNil())
+
+    got := idgen.FromParts(1, 2, 3, 4, 5)
+    if err := got.Scan(nil); err != nil || !got.IsNil() {
+        t.Fatalf("Scan(nil) = %s, %v, want NilID", got, err)
+    }
+
+    idgen.SetEmptyAsNil(true)
+    if v, err := idgen.NilID.Value(); err != nil || v != nil {
+        t.Fatalf("NilID.Value() = %v, %v, want NULL", v, err)
+    }
+    idgen.SetEmptyAsNil(false)
+    if v, err := idgen.NilID.Value(); err != nil || v == nil {
+        t.Fatalf("NilID.Value() without EmptyAsNil = %v, %v", v, err)
+    }
+}
+
+func TestSQLScanRejects(t *testing.T) {
+    tests := []struct {
+        name string
+        src  any
+        want error
+    }{
+        {"int", int64(1), idgen.ErrInvalidEncoding},
+        {"short bytes", []byte{1, 2, 3}, idgen.ErrInvalidLength},
+        {"bad hex", "zz000000000000000000000000000000", idgen.ErrInvalidEncoding},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            var id idgen.ID
+            if err := id.Scan(tt.src); !errors.Is(err, tt.want) {
+                t.Fatalf("Scan(%v) error = %v, want %v", tt.src, err, tt.want)
+            }
+        })
+    }
+}
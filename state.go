@@ -0,0 +1,155 @@
+package idgen
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io/fs"
+    "math"
+    "os"
+    "path/filepath"
+    "time"
+)
+
+// ------------- 狀態持久化 ------------- //
+
+// statePersistAhead 為每次寫入時預留的時間窗口
+// 寫入的 LastMillis 為目前時間加上此窗口，窗口內產生 ID 不需再寫入，約每秒最多寫入一次
+const statePersistAhead = time.Second
+
+// State 為 Generator 需要跨重啟保存的狀態
+// LastMillis 為已承諾的時間上限：重啟前發出的 ID 時間戳皆不超過此值
+type State struct {
+    Epoch      uint16 `json:"epoch"`
+    LastMillis uint64 `json:"last_millis"`
+}
+
+// StateStore 持久化 Generator 狀態；Load 在沒有任何紀錄時回傳 ok=false
+// Save 回傳前必須確保資料已可靠寫入
+type StateStore interface {
+    Load() (st State, ok bool, err error)
+    Save(st State) error
+}
+
+// FileStateStore 以 JSON 檔案保存狀態
+// 寫入時先寫暫存檔並 fsync 後再 rename，確保檔案內容永遠完整
+type FileStateStore struct {
+    Path string
+}
+
+// NewFileStateStore 建立保存於 path 的 FileStateStore
+func NewFileStateStore(path string) *FileStateStore {
+    return &FileStateStore{Path: path}
+}
+
+// Load 實作 StateStore，檔案不存在時回傳 ok=false
+func (s *FileStateStore) Load() (State, bool, error) {
+    var st State
+    b, err := os.ReadFile(s.Path)
+    if errors.Is(err, fs.ErrNotExist) {
+        return st, false, nil
+    }
+    if err != nil {
+        return st, false, err
+    }
+    if err := json.Unmarshal(b, &st); err != nil {
+        return st, false, fmt.Errorf("idgen: corrupt state file %s: %w", s.Path, err)
+    }
+    return st, true, nil
+}
+
+// Save 實作 StateStore
+func (s *FileStateStore) Save(st State) error {
+    b, err := json.Marshal(st)
+    if err != nil {
+        return err
+    }
+    f, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".tmp*")
+    if err != nil {
+        return err
+    }
+    tmp := f.Name()
+    if _, err = f.Write(b); err == nil {
+        err = f.Sync()
+    }
+    if cerr := f.Close(); err == nil {
+        err = cerr
+    }
+    if err == nil {
+        err = os.Rename(tmp, s.Path)
+    }
+    if err != nil {
+        os.Remove(tmp)
+    }
+    return err
+}
+
+// restore 於建立時依設定載入狀態並處理啟動時的 epoch 提升
+func (g *Generator) restore() error {
+    if g.startupBump && g.epochStore == nil && g.store == nil {
+        return errors.New("idgen: startup epoch bump requires a state store")
+    }
+    if g.store != nil {
+        if err := g.restoreState(); err != nil {
+            return err
+        }
+    }
+    if g.startupBump && g.epochStore != nil {
+        return g.bumpStartupEpoch()
+    }
+    return nil
+}
+
+// restoreState 載入先前狀態
+// 目前時間未超過上次承諾的時間上限 (重啟時時鐘回撥或重啟過快) 或要求啟動時提升時提升 epoch，
+// 確保新 ID 不會與重啟前發出的 ID 重疊
+func (g *Generator) restoreState() error {
+    st, ok, err := g.store.Load()
+    if err != nil {
+        return err
+    }
+    now := g.currentMillis()
+    if now > math.MaxInt64 {
+        return g.beforeEpochError()
+    }
+    if ok {
+        g.epoch = st.Epoch
+        g.lastToken = g.tokenFloor(st.LastMillis)
+        // 時間戳模糊化時同一區間內的位置不遞增，重啟後仍在同一區間也須提升
+        if g.privacy.coarse(now) <= g.privacy.coarse(st.LastMillis) || g.startupBump && g.epochStore == nil {
+            g.bumpEpoch(g.epoch + 1)
+        }
+    }
+    return g.persist(now)
+}
+
+// bumpStartupEpoch 自 epochStore 讀取上次啟動的 epoch，加一後立即寫回
+func (g *Generator) bumpStartupEpoch() error {
+    st, ok, err := g.epochStore.Load()
+    if err != nil {
+        return err
+    }
+    if ok {
+        g.epoch = st.Epoch
+        g.lastToken = g.tokenFloor(st.LastMillis)
+        g.bumpEpoch(st.Epoch + 1)
+    }
+    st.Epoch = g.epoch
+    if err := g.epochStore.Save(st); err != nil {
+        return fmt.Errorf("idgen: persist startup epoch: %w", err)
+    }
+    return nil
+}
+
+// persist 在 epoch 改變或 now 超出已承諾的時間上限時寫入新狀態，呼叫端須持有 g.mu (或於建立期間)
+func (g *Generator) persist(now uint64) error {
+    if g.store == nil || (g.epoch == g.persisted.Epoch && now <= g.persisted.LastMillis) {
+        return nil
+    }
+    st := State{Epoch: g.epoch, LastMillis: now + uint64(statePersistAhead/g.tick)}
+    if err := g.store.Save(st); err != nil {
+        return fmt.Errorf("idgen: persist state: %w", err)
+    }
+    g.persisted = st
+    return nil
+}
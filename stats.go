@@ -0,0 +1,60 @@
+package idgen
+
+import (
+    "sync/atomic"
+    "time"
+)
+
+// ------------- 統計快照 ------------- //
+
+// Stats 為 Generator 自建立以來的統計與目前狀態
+type Stats struct {
+    IDsIssued         uint64 // 已產生的 ID 數量
+    ClockRollbacks    uint64 // 偵測到時鐘回撥的次數
+    EpochBumps        uint64 // 提升 epoch 的次數 (含回撥與啟動時)
+    SequenceExhausted uint64 // 序列號用盡的次數
+    LastMillis        uint64 // 最近一個 ID 的時間戳
+    Epoch             uint16 // 目前的 epoch
+}
+
+// statsRecorder 包裝使用者設定的 Metrics，在轉發前累計 Stats 所需的計數
+type statsRecorder struct {
+    next Metrics
+
+    issued    atomic.Uint64
+    rollbacks atomic.Uint64
+    bumps     atomic.Uint64
+    exhausted atomic.Uint64
+}
+
+func (r *statsRecorder) IncCounter(name string, delta uint64) {
+    switch name {
+    case MetricIDsIssued:
+        r.issued.Add(delta)
+    case MetricClockRollbacks:
+        r.rollbacks.Add(delta)
+    case MetricEpochBumps:
+        r.bumps.Add(delta)
+    case MetricSequenceExhausted:
+        r.exhausted.Add(delta)
+    }
+    r.next.IncCounter(name, delta)
+}
+
+func (r *statsRecorder) ObserveDuration(name string, d time.Duration) {
+    r.next.ObserveDuration(name, d)
+}
+
+// Stats 回傳目前的統計快照 (thread‑safe)
+// ShardedGenerator 與 Compact64 的計數累計在建立時的 Generator 上
+func (g *Generator) Stats() Stats {
+    g.mu.Lock()
+    st := Stats{LastMillis: g.lastMillis, Epoch: g.epoch}
+    g.mu.Unlock()
+
+    st.IDsIssued = g.stats.issued.Load()
+    st.ClockRollbacks = g.stats.rollbacks.Load()
+    st.EpochBumps = g.stats.bumps.Load()
+    st.SequenceExhausted = g.stats.exhausted.Load()
+    return st
+}
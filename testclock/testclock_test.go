@@ -0,0 +1,128 @@
+package testclock_test
+
+import (
+    "errors"
+    "testing"
+    "time"
+
+    "github.com/pascal910107/idgen"
+    "github.com/pascal910107/idgen/testclock"
+)
+
+var start = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func TestRollback(t *testing.T) {
+    tests := []struct {
+        name      string
+        policy    idgen.RollbackPolicy
+        rollback  time.Duration
+        wantErr   error
+        wantEpoch uint16
+        wantTS    int64 // 回撥後第一個 ID 的時間戳相對 start 的毫秒數
+    }{
+        {"wait within limit", idgen.WaitUpTo(5 * time.Millisecond), 3 * time.Millisecond, nil, 0, 10},
+        {"bump beyond limit", idgen.WaitUpTo(5 * time.Millisecond), time.Second, nil, 1, -990},
+        {"bump immediately", idgen.BumpEpoch(), time.Millisecond, nil, 1, 9},
+        {"return error", idgen.ReturnError(), time.Second, idgen.ErrClockRollback, 0, 0},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            g, clk, err := testclock.NewGenerator(start,
+                idgen.WithCustomEpoch(start.Add(-time.Second)), idgen.WithRollbackPolicy(tt.policy))
+            if err != nil {
+                t.Fatal(err)
+            }
+            clk.Advance(10 * time.Millisecond)
+            prev, err := g.Next()
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            clk.Advance(-tt.rollback)
+            id, err := g.Next()
+            if !errors.Is(err, tt.wantErr) {
+                t.Fatalf("err = %v, want %v", err, tt.wantErr)
+            }
+            if err != nil {
+                return
+            }
+            if id.Compare(prev) <= 0 {
+                t.Fatalf("%s not after %s", id, prev)
+            }
+            if id.Epoch() != tt.wantEpoch {
+                t.Fatalf("epoch = %d, want %d", id.Epoch(), tt.wantEpoch)
+            }
+            if got := int64(id.TimestampMillis()) - 1000; got != tt.wantTS {
+                t.Fatalf("timestamp = start+%dms, want start+%dms", got, tt.wantTS)
+            }
+        })
+    }
+}
+
+func TestDeterministic(t *testing.T) {
+    type step struct {
+        advance time.Duration
+        n       int
+    }
+    tests := []struct {
+        name  string
+        opts  []idgen.Option
+        steps []step
+    }{
+        {"same millisecond", nil, []step{{0, 3}}},
+        {"advancing", []idgen.Option{idgen.WithRegionNode(1, 42)}, []step{{0, 2}, {time.Millisecond, 1}, {time.Hour, 2}}},
+        {"microsecond precision", []idgen.Option{idgen.WithPrecision(time.Microsecond)}, []step{{0, 1}, {time.Microsecond, 2}}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            run := func() []idgen.ID {
+                opts := append([]idgen.Option{idgen.WithCustomEpoch(start.Add(-time.Second))}, tt.opts...)
+                g, clk, err := testclock.NewGenerator(start, opts...)
+                if err != nil {
+                    t.Fatal(err)
+                }
+                var ids []idgen.ID
+                for _, s := range tt.steps {
+                    clk.Advance(s.advance)
+                    batch, err := g.NextN(s.n)
+                    if err != nil {
+                        t.Fatal(err)
+                    }
+                    ids = append(ids, batch...)
+                }
+                return ids
+            }
+
+            first, second := run(), run()
+            for i := range first {
+                if first[i] != second[i] {
+                    t.Fatalf("id %d differs between runs: %s vs %s", i, first[i], second[i])
+                }
+            }
+        })
+    }
+}
+
+func TestGolden(t *testing.T) {
+    g, clk, err := testclock.NewGenerator(start, idgen.WithCustomEpoch(start.Add(-time.Second)), idgen.WithRegionNode(1, 42))
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := []string{
+        "000000000000000003e80001002a0000",
+        "000000000000000003e80001002a0001",
+        "000000000000000003e90001002a0000",
+    }
+    for i, w := range want {
+        if i == 2 {
+            clk.Advance(time.Millisecond)
+        }
+        id, err := g.Next()
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got := id.Hex(); got != w {
+            t.Fatalf("id %d = %s, want %s", i, got, w)
+        }
+    }
+}
@@ -0,0 +1,52 @@
+package idgen
+
+import "time"
+
+// ------------- 時間相關工具 ------------- //
+
+// MinIDAt 回傳時間 t 所在毫秒內最小的 ID (epoch、region、node、sequence 皆為 0)
+// 搭配 MaxIDAt 可做 `WHERE id BETWEEN ? AND ?` 的時間範圍查詢
+// 以 CustomEpoch 為起算點；早於起算點的時間視為 0
+// 注意：因時鐘回撥而提升 epoch 的 ID 不在此範圍內，需要時請逐一 epoch 查詢
+func MinIDAt(t time.Time) ID {
+    return makeID(0, millisSince(t, CustomEpoch), 0, 0, 0)
+}
+
+// MaxIDAt 回傳時間 t 所在毫秒內最大的 ID (epoch 為 0，region、node、sequence 皆為最大值)
+func MaxIDAt(t time.Time) ID {
+    return makeID(0, millisSince(t, CustomEpoch), maxRegion, maxNode, maxSequence)
+}
+
+// millisSince 回傳 t 相對 epochMillis 的毫秒數，早於起算點時回傳 0
+func millisSince(t time.Time, epochMillis int64) uint64 {
+    ms := t.UnixMilli() - epochMillis
+    if ms < 0 {
+        return 0
+    }
+    return uint64(ms)
+}
+
+// Time 將內嵌的毫秒時間戳轉回 time.Time，以 CustomEpoch 為起算點
+// 由自訂起算點的 Generator 產生的 ID 請改用 Generator.Time
+func (id ID) Time() time.Time {
+    return timeAt(id.TimestampMillis(), CustomEpoch)
+}
+
+// Age 回傳 ID 產生至今經過的時間，即 time.Since(id.Time())
+func (id ID) Age() time.Duration {
+    return time.Since(id.Time())
+}
+
+// Time 以此 Generator 的起算點將 id 的時間戳轉回 time.Time
+func (g *Generator) Time(id ID) time.Time {
+    ts := g.layout.Decode(id).TimestampMillis
+    if g.tick == time.Microsecond {
+        return time.UnixMicro(g.customEpoch*1000 + int64(ts)).UTC()
+    }
+    return timeAt(ts, g.customEpoch)
+}
+
+// timeAt 將相對 epochMillis 的毫秒數轉為 UTC time.Time
+func timeAt(ms uint64, epochMillis int64) time.Time {
+    return time.UnixMilli(epochMillis + int64(ms)).UTC()
+}
@@ -0,0 +1,74 @@
+package idgen
+
+import (
+    "encoding/binary"
+    "fmt"
+)
+
+// ------------- ULID 互通 ------------- //
+
+// ToULID 將 ID 轉為 ULID (以 CustomEpoch 換算絕對時間)，回傳值可直接指定給 oklog/ulid.ULID
+//
+// 位元配置：
+//
+//    timestamp (48) │ randomness (80)
+//    Unix 毫秒      │ epoch 16 │ region 16 │ node 16 │ sequence 16 │ 0 (16)
+//
+// 同一 epoch 內轉換前後排序一致；時間超出 48-bit Unix 毫秒時回傳 ErrInvalidEncoding
+func (id ID) ToULID() ([16]byte, error) {
+    var u [16]byte
+    unixMs := CustomEpoch + int64(id.TimestampMillis())
+    if unixMs < 0 || unixMs >= 1<<48 || id.TimestampMillis() >= 1<<63 {
+        return u, fmt.Errorf("%w: timestamp does not fit ulid", ErrInvalidEncoding)
+    }
+
+    binary.BigEndian.PutUint64(u[0:8], uint64(unixMs)<<16|uint64(id.Epoch()))
+    binary.BigEndian.PutUint16(u[8:10], id.Region())
+    binary.BigEndian.PutUint16(u[10:12], id.Node())
+    binary.BigEndian.PutUint16(u[12:14], id.Sequence())
+    return u, nil
+}
+
+// ULIDString 回傳 ToULID 結果的 26 字元 ULID 字串
+func (id ID) ULIDString() (string, error) {
+    u, err := id.ToULID()
+    if err != nil {
+        return "", err
+    }
+    return ID(u).Base32Crockford(), nil
+}
+
+// FromULID 為 ToULID 的反向轉換
+// 對其他來源的 ULID，隨機位元會被解讀為 epoch/region/node/sequence，最低 16 bits 會被捨棄
+// 時間早於 CustomEpoch 時回傳 ErrInvalidEncoding
+func FromULID(u [16]byte) (ID, error) {
+    hi := binary.BigEndian.Uint64(u[0:8])
+    unixMs := int64(hi >> 16)
+    if unixMs < CustomEpoch {
+        return ID{}, fmt.Errorf("%w: ulid timestamp before custom epoch", ErrInvalidEncoding)
+    }
+    return makeID(
+        uint16(hi),
+        uint64(unixMs-CustomEpoch),
+        binary.BigEndian.Uint16(u[8:10]),
+        binary.BigEndian.Uint16(u[10:12]),
+        binary.BigEndian.Uint16(u[12:14]),
+    ), nil
+}
+
+// ParseULID 解析 26 字元 ULID 字串並以 FromULID 轉為 ID，不經 isULID 判斷
+func ParseULID(s string) (ID, error) {
+    u, err := ParseBase32Crockford(s)
+    if err != nil {
+        return ID{}, err
+    }
+    return FromULID(u)
+}
+
+// isULID 判斷 26 字元字串解碼後的 u 應視為 ULID 或 ID 本身的 Crockford 編碼：
+// 前 48 bits 視為 Unix 毫秒時不早於 CustomEpoch 者為 ULID (FromULID 也只接受這個範圍)
+// ID 的前 48 bits 為 epoch<<32 | 時間戳的高 32 bits，epoch 小於 CustomEpoch>>32 (預設為 404) 時必小於 CustomEpoch，
+// 因此不會被誤判；epoch 提升到此值以上的 ID 請以 ParseBase32Crockford 解析
+func isULID(u ID) bool {
+    return CustomEpoch > 0 && int64(binary.BigEndian.Uint64(u[0:8])>>16) >= CustomEpoch
+}
@@ -0,0 +1,72 @@
+package idgen_test
+
+import (
+    "errors"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/pascal910107/idgen"
+)
+
+func TestParseULID(t *testing.T) {
+    g, err := idgen.New(idgen.WithRegionNode(3, 7))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer g.Release()
+    id, err := g.Next()
+    if err != nil {
+        t.Fatal(err)
+    }
+    ulid, err := id.ULIDString()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    tests := []struct {
+        name string
+        in   string
+        want idgen.ID
+    }{
+        {"ulid", ulid, id},
+        {"lower case ulid", strings.ToLower(ulid), id},
+        {"ulid at custom epoch", "01JGFJJZ000000000000000000", idgen.FromParts(0, 0, 0, 0, 0)},
+        {"crockford", id.Base32Crockford(), id},
+        {"crockford epoch 403", idgen.FromParts(403, 1<<40, 1, 2, 3).Base32Crockford(), idgen.FromParts(403, 1<<40, 1, 2, 3)},
+        // 早於 CustomEpoch 的 ULID 無法以 FromULID 轉換，視為 ID 本身的編碼
+        {"ulid before custom epoch", "01ARZ3NDEKTSV4RRFFQ69G5FAV", mustParseCrockford(t, "01ARZ3NDEKTSV4RRFFQ69G5FAV")},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got, err := idgen.Parse(tt.in)
+            if err != nil || got != tt.want {
+                t.Fatalf("Parse(%q) = %s, %v, want %s", tt.in, got, err, tt.want)
+            }
+        })
+    }
+}
+
+func TestULIDRoundTrip(t *testing.T) {
+    id := idgen.FromParts(2, uint64(time.Hour.Milliseconds()), 1, 2, 3)
+    u, err := id.ToULID()
+    if err != nil {
+        t.Fatal(err)
+    }
+    back, err := idgen.FromULID(u)
+    if err != nil || back != id {
+        t.Fatalf("FromULID(ToULID(%s)) = %s, %v", id, back, err)
+    }
+    if _, err := idgen.FromULID([16]byte{}); !errors.Is(err, idgen.ErrInvalidEncoding) {
+        t.Fatalf("FromULID(zero) error = %v, want ErrInvalidEncoding", err)
+    }
+}
+
+func mustParseCrockford(t *testing.T, s string) idgen.ID {
+    t.Helper()
+    id, err := idgen.ParseBase32Crockford(s)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return id
+}
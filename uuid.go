@@ -0,0 +1,105 @@
+package idgen
+
+import (
+    "encoding/binary"
+    "encoding/hex"
+    "fmt"
+)
+
+// ------------- UUID 字串格式 ------------- //
+
+// UUIDString 回傳 8-4-4-4-12 的 UUID 標準字串 (36 字元，小寫)
+// 僅改變呈現方式，不設定 UUID version/variant 位元
+func (id ID) UUIDString() string {
+    return string(id.AppendUUID(make([]byte, 0, 36)))
+}
+
+// AppendUUID 將 UUID 字串附加到 dst 後回傳，dst 容量足夠時不配置記憶體
+func (id ID) AppendUUID(dst []byte) []byte {
+    var buf [36]byte
+    hex.Encode(buf[0:8], id[0:4])
+    buf[8] = '-'
+    hex.Encode(buf[9:13], id[4:6])
+    buf[13] = '-'
+    hex.Encode(buf[14:18], id[6:8])
+    buf[18] = '-'
+    hex.Encode(buf[19:23], id[8:10])
+    buf[23] = '-'
+    hex.Encode(buf[24:36], id[10:16])
+    return append(dst, buf[:]...)
+}
+
+// parseUUID 解析 8-4-4-4-12 格式的 UUID 字串
+func parseUUID(s string) (ID, error) {
+    var id ID
+    if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+        return id, fmt.Errorf("%w: uuid string %q", ErrInvalidEncoding, s)
+    }
+    var buf [32]byte
+    copy(buf[0:8], s[0:8])
+    copy(buf[8:12], s[9:13])
+    copy(buf[12:16], s[14:18])
+    copy(buf[16:20], s[19:23])
+    copy(buf[20:32], s[24:36])
+    if _, err := hex.Decode(id[:], buf[:]); err != nil {
+        return ID{}, fmt.Errorf("%w: %v", ErrInvalidEncoding, err)
+    }
+    return id, nil
+}
+
+// ------------- UUIDv7 轉換 ------------- //
+
+// ToUUIDv7 將 ID 轉為 RFC 9562 UUIDv7 (以 CustomEpoch 換算絕對時間)
+// 回傳值可直接指定給 google/uuid.UUID 等 [16]byte 型別
+//
+// 位元配置：
+//
+//    unix_ts_ms (48) │ ver=7 (4) │ rand_a (12)        │ var=10 (2) │ rand_b (62)
+//    Unix 毫秒       │           │ sequence 高 12 bits │            │ sequence 低 4 │ epoch 16 │ region 16 │ node 16 │ 0 (10)
+//
+// 同一毫秒內的 UUIDv7 仍依序列號排序，但 epoch 位於序列號之後，
+// 因此跨 epoch (時鐘回撥) 的 ID 轉換後不保證維持原本順序
+// 時間超出 48-bit Unix 毫秒 (約西元 10889 年) 時回傳 ErrInvalidEncoding
+func (id ID) ToUUIDv7() ([16]byte, error) {
+    var u [16]byte
+    unixMs := CustomEpoch + int64(id.TimestampMillis())
+    if unixMs < 0 || unixMs >= 1<<48 || id.TimestampMillis() >= 1<<63 {
+        return u, fmt.Errorf("%w: timestamp does not fit uuidv7", ErrInvalidEncoding)
+    }
+
+    seq := uint64(id.Sequence())
+    hi := uint64(unixMs)<<16 | 0x7<<12 | seq>>4
+    lo := 0b10<<62 |
+        (seq&0xF)<<58 |
+        uint64(id.Epoch())<<42 |
+        uint64(id.Region())<<26 |
+        uint64(id.Node())<<10
+    binary.BigEndian.PutUint64(u[0:8], hi)
+    binary.BigEndian.PutUint64(u[8:16], lo)
+    return u, nil
+}
+
+// FromUUIDv7 為 ToUUIDv7 的反向轉換
+// 對 ToUUIDv7 的輸出可完整還原；對其他來源的 UUIDv7，隨機位元會被解讀為
+// sequence/epoch/region/node，且 rand_b 最低 10 bits 會被捨棄
+// u 不是 UUIDv7 (version 7、variant 10) 或時間早於 CustomEpoch 時回傳錯誤
+func FromUUIDv7(u [16]byte) (ID, error) {
+    hi := binary.BigEndian.Uint64(u[0:8])
+    lo := binary.BigEndian.Uint64(u[8:16])
+    if hi>>12&0xF != 7 || lo>>62 != 0b10 {
+        return ID{}, fmt.Errorf("%w: not a uuidv7", ErrInvalidEncoding)
+    }
+
+    unixMs := int64(hi >> 16)
+    if unixMs < CustomEpoch {
+        return ID{}, fmt.Errorf("%w: uuidv7 timestamp before custom epoch", ErrInvalidEncoding)
+    }
+    seq := uint16(hi&0xFFF)<<4 | uint16(lo>>58&0xF)
+    return makeID(
+        uint16(lo>>42),
+        uint64(unixMs-CustomEpoch),
+        uint16(lo>>26),
+        uint16(lo>>10),
+        seq,
+    ), nil
+}
@@ -0,0 +1,78 @@
+package idgen
+
+import (
+    "fmt"
+    "math"
+    "time"
+)
+
+// ------------- 語意驗證 ------------- //
+
+// Validator 檢查 ID 的欄位是否合理，用於 API 邊界的輸入驗證
+// 零值即可使用，各欄位為零值時採用預設行為
+type Validator struct {
+    // MaxFuture 允許 ID 時間戳領先目前時間的上限，預設 1 分鐘
+    MaxFuture time.Duration
+    // MaxEpoch 允許的最大 epoch 值，0 代表不限制
+    MaxEpoch uint16
+    // CustomEpoch 時間戳起算點 (Unix 毫秒)，0 代表使用套件的 CustomEpoch
+    CustomEpoch int64
+    // Clock 取得目前時間的來源，nil 代表系統時鐘
+    Clock Clock
+}
+
+// DefaultValidator 為 ParseStrict 與 ID.Validate 使用的驗證設定
+var DefaultValidator = &Validator{}
+
+// Validate 依 v 的設定檢查 id，不合理時回傳包裝 ErrImplausibleID 的錯誤
+// 時間戳為 0 (含 NilID) 或換算後早於起算點者視為不合理
+func (v *Validator) Validate(id ID) error {
+    ts := id.TimestampMillis()
+    if ts == 0 || ts > math.MaxInt64 {
+        return fmt.Errorf("%w: timestamp %d before custom epoch", ErrImplausibleID, int64(ts))
+    }
+
+    epochMillis := v.CustomEpoch
+    if epochMillis == 0 {
+        epochMillis = CustomEpoch
+    }
+    clock := v.Clock
+    if clock == nil {
+        clock = SystemClock()
+    }
+    maxFuture := v.MaxFuture
+    if maxFuture <= 0 {
+        maxFuture = time.Minute
+    }
+    // 以整數毫秒比較，避免極大的時間戳換算為 time.Time 時溢位成負值而通過檢查
+    if limit := clock.Now().Add(maxFuture).UnixMilli() - epochMillis; limit < 0 || ts > uint64(limit) {
+        return fmt.Errorf("%w: timestamp %d is more than %v in the future", ErrImplausibleID, ts, maxFuture)
+    }
+
+    if v.MaxEpoch != 0 && id.Epoch() > v.MaxEpoch {
+        return fmt.Errorf("%w: epoch %d exceeds %d", ErrImplausibleID, id.Epoch(), v.MaxEpoch)
+    }
+    return nil
+}
+
+// Parse 以 Parse 解析 s 後再以 v 驗證
+func (v *Validator) Parse(s string) (ID, error) {
+    id, err := Parse(s)
+    if err != nil {
+        return id, err
+    }
+    if err := v.Validate(id); err != nil {
+        return ID{}, err
+    }
+    return id, nil
+}
+
+// ParseStrict 解析 s 並以 DefaultValidator 驗證其語意
+func ParseStrict(s string) (ID, error) {
+    return DefaultValidator.Parse(s)
+}
+
+// Validate 以 DefaultValidator 檢查 id 的欄位是否合理
+func (id ID) Validate() error {
+    return DefaultValidator.Validate(id)
+}
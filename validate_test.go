@@ -0,0 +1,84 @@
+package idgen_test
+
+import (
+    "encoding/binary"
+    "errors"
+    "math"
+    "testing"
+    "time"
+
+    "github.com/pascal910107/idgen"
+    "github.com/pascal910107/idgen/testclock"
+)
+
+func TestValidatorValidate(t *testing.T) {
+    now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+    ms := uint64(now.UnixMilli() - idgen.CustomEpoch)
+    v := &idgen.Validator{Clock: testclock.New(now), MaxEpoch: 3}
+
+    tests := []struct {
+        name string
+        id   idgen.ID
+        ok   bool
+    }{
+        {"now", idgen.FromParts(0, ms, 1, 2, 3), true},
+        {"within max future", idgen.FromParts(0, ms+30_000, 1, 2, 3), true},
+        {"beyond max future", idgen.FromParts(0, ms+120_000, 1, 2, 3), false},
+        {"zero timestamp", idgen.FromParts(0, 0, 1, 2, 3), false},
+        {"nil id", idgen.ID{}, false},
+        {"overflows with epoch", idgen.FromParts(0, uint64(math.MaxInt64-idgen.CustomEpoch)+1, 1, 2, 3), false},
+        {"max int64", idgen.FromParts(0, math.MaxInt64, 1, 2, 3), false},
+        {"max uint64", idWithTimestamp(math.MaxUint64), false},
+        {"epoch too large", idgen.FromParts(4, ms, 1, 2, 3), false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            err := v.Validate(tt.id)
+            if tt.ok && err != nil {
+                t.Fatalf("Validate: %v", err)
+            }
+            if !tt.ok && !errors.Is(err, idgen.ErrImplausibleID) {
+                t.Fatalf("Validate error = %v, want ErrImplausibleID", err)
+            }
+        })
+    }
+}
+
+func TestParseStrict(t *testing.T) {
+    g, err := idgen.New(idgen.WithRegionNode(1, 2))
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer g.Release()
+    id, err := g.Next()
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    if got, err := idgen.ParseStrict(id.String()); err != nil || got != id {
+        t.Fatalf("ParseStrict(%s) = %s, %v", id, got, err)
+    }
+    tests := []struct {
+        name string
+        in   string
+        want error
+    }{
+        {"far future", idgen.FromParts(0, math.MaxInt64, 1, 2, 3).String(), idgen.ErrImplausibleID},
+        {"nil", idgen.ID{}.String(), idgen.ErrImplausibleID},
+        {"bad length", "abc", idgen.ErrInvalidLength},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if _, err := idgen.ParseStrict(tt.in); !errors.Is(err, tt.want) {
+                t.Fatalf("ParseStrict(%q) error = %v, want %v", tt.in, err, tt.want)
+            }
+        })
+    }
+}
+
+// idWithTimestamp 直接寫入時間戳欄位，模擬 FromParts 不允許建構的惡意輸入
+func idWithTimestamp(ts uint64) idgen.ID {
+    var id idgen.ID
+    binary.BigEndian.PutUint64(id[2:10], ts)
+    return id
+}